@@ -1,7 +1,9 @@
 package onedriveclient
 
 import (
+	"net/url"
 	"path"
+	"strings"
 )
 
 const (
@@ -48,6 +50,135 @@ func AddressPath(pth string) Address {
 	}
 }
 
+// AddressFromItem returns a path address for item if its parent path is
+// known and it is in drive driveId, otherwise an id address.
+func AddressFromItem(item *Item, driveId string) Address {
+	parent := item.ParentReference
+
+	if parent == nil || parent.Path == "" {
+		return AddressId(item.Id)
+	}
+
+	if driveId != "" && parent.DriveId != "" && !strings.EqualFold(driveId, parent.DriveId) {
+		return AddressId(item.Id)
+	}
+
+	i := strings.Index(parent.Path, "root:")
+	if i == -1 {
+		return AddressId(item.Id)
+	}
+
+	parentPath, err := url.PathUnescape(parent.Path[i+len("root:"):])
+	if err != nil {
+		return AddressId(item.Id)
+	}
+
+	address, err := AddressPath(parentPath).Join(item.Name)
+	if err != nil {
+		return AddressId(item.Id)
+	}
+
+	return address
+}
+
+func validateSegment(name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return &InvalidNameError{
+			Name:   name,
+			Reason: "name is not a single path segment",
+		}
+	}
+
+	return nil
+}
+
+// Join appends names to the address. Every name is exactly one path segment,
+// so empty names, ".", ".." and names containing "/" are rejected.
+func (a Address) Join(names ...string) (address Address, err error) {
+	prefix, pth, ok := a.splitPath()
+	if !ok {
+		pth = "/"
+	}
+
+	if prefix == AddressRoot.Address {
+		prefix = "/root"
+	}
+
+	pth = NormalizePath(pth)
+
+	for _, name := range names {
+		if err := validateSegment(name); err != nil {
+			return Address{}, err
+		}
+
+		if pth == "/" {
+			pth += name
+		} else {
+			pth += "/" + name
+		}
+	}
+
+	return Address{
+		Address: prefix + ":" + pth + ":",
+		Type:    AddressTypePath,
+	}, nil
+}
+
+// Parent returns the parent folder of a path address. Id addresses are
+// returned unchanged because their parent cannot be known without a request.
+func (a Address) Parent() Address {
+	prefix, pth, ok := a.splitPath()
+	if !ok {
+		return a
+	}
+
+	parent := path.Dir(NormalizePath(pth))
+
+	if parent == "/" {
+		if prefix == "/root" {
+			return AddressRoot
+		}
+
+		return Address{
+			Address: prefix,
+			Type:    AddressTypeId,
+		}
+	}
+
+	return Address{
+		Address: prefix + ":" + parent + ":",
+		Type:    AddressTypePath,
+	}
+}
+
+func (a Address) Base() string {
+	_, pth, ok := a.splitPath()
+	if !ok {
+		return ""
+	}
+
+	pth = NormalizePath(pth)
+
+	if pth == "/" {
+		return ""
+	}
+
+	return path.Base(pth)
+}
+
 func NormalizePath(pth string) string {
 	return path.Clean("/" + pth)
 }
+
+func (a Address) splitPath() (prefix string, pth string, ok bool) {
+	if a.Type != AddressTypePath {
+		return a.Address, "", false
+	}
+
+	i := strings.Index(a.Address, ":")
+	if i == -1 {
+		return a.Address, "", false
+	}
+
+	return a.Address[:i], strings.TrimSuffix(a.Address[i+1:], ":"), true
+}
@@ -0,0 +1,95 @@
+package onedriveclient
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Address", func() {
+	Describe("Join", func() {
+		It("should join names to a path address", func() {
+			Expect(AddressPath("/a").Join("b", "c.txt")).To(Equal(AddressPath("/a/b/c.txt")))
+		})
+
+		It("should treat every name as one segment", func() {
+			for _, name := range []string{"", ".", "..", "a/b", "/a"} {
+				_, err := AddressPath("/a").Join(name)
+				_, ok := IsInvalidNameError(err)
+				Expect(ok).To(BeTrue(), name)
+			}
+		})
+
+		It("should join names to root", func() {
+			Expect(AddressRoot.Join("a", "b")).To(Equal(AddressPath("/a/b")))
+		})
+
+		It("should join names to an id address", func() {
+			address, err := AddressId("123").Join("a", "b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(address.Type).To(Equal(AddressTypePath))
+			Expect(address.String("")).To(Equal("/drive/items/123:/a/b:"))
+		})
+	})
+
+	Describe("Parent", func() {
+		It("should get parent of a path address", func() {
+			Expect(AddressPath("/a/b/c.txt").Parent()).To(Equal(AddressPath("/a/b")))
+			Expect(AddressPath("/a").Parent()).To(Equal(AddressRoot))
+			Expect(AddressPath("/").Parent()).To(Equal(AddressRoot))
+			address, _ := AddressId("123").Join("a")
+			Expect(address.Parent()).To(Equal(AddressId("123")))
+		})
+
+		It("should return id addresses unchanged", func() {
+			Expect(AddressId("123").Parent()).To(Equal(AddressId("123")))
+		})
+	})
+
+	Describe("Base", func() {
+		It("should get base name", func() {
+			Expect(AddressPath("/a/b/c.txt").Base()).To(Equal("c.txt"))
+			Expect(AddressPath("/").Base()).To(Equal(""))
+			Expect(AddressId("123").Base()).To(Equal(""))
+		})
+	})
+
+	Describe("AddressFromItem", func() {
+		It("should derive path address from parent reference", func() {
+			Expect(AddressFromItem(&Item{
+				Id:              "1",
+				Name:            "file.txt",
+				ParentReference: &ItemReference{Path: "/drive/root:"},
+			}, "")).To(Equal(AddressPath("/file.txt")))
+
+			Expect(AddressFromItem(&Item{
+				Id:              "1",
+				Name:            "file.txt",
+				ParentReference: &ItemReference{DriveId: "ABC", Path: "/drives/abc/root:/dir/sub"},
+			}, "abc")).To(Equal(AddressPath("/dir/sub/file.txt")))
+		})
+
+		It("should decode the parent path", func() {
+			Expect(AddressFromItem(&Item{
+				Id:              "1",
+				Name:            "f.txt",
+				ParentReference: &ItemReference{Path: "/drive/root:/My%20Folder"},
+			}, "")).To(Equal(AddressPath("/My Folder/f.txt")))
+		})
+
+		It("should fall back to id address", func() {
+			Expect(AddressFromItem(&Item{Id: "1", Name: "file.txt"}, "")).To(Equal(AddressId("1")))
+
+			Expect(AddressFromItem(&Item{
+				Id:              "1",
+				Name:            "file.txt",
+				ParentReference: &ItemReference{DriveId: "other", Path: "/drives/other/root:/dir"},
+			}, "abc")).To(Equal(AddressId("1")))
+
+			Expect(AddressFromItem(&Item{
+				Id:              "1",
+				Name:            "..",
+				ParentReference: &ItemReference{Path: "/drive/root:/dir"},
+			}, "")).To(Equal(AddressId("1")))
+		})
+	})
+})
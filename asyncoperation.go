@@ -0,0 +1,86 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AsyncOperation is a handle to a server-side copy that can be stored and
+// resumed by another process using a client of the same kind. DriveId is the
+// drive the copied item is created in.
+type AsyncOperation struct {
+	MonitorUrl           string    `json:"monitorUrl"`
+	IsGraph              bool      `json:"isGraph"`
+	DriveId              string    `json:"driveId,omitempty"`
+	NameConflictBehavior string    `json:"nameConflictBehavior,omitempty"`
+	CreatedAt            time.Time `json:"createdAt"`
+}
+
+func ParseAsyncOperation(data []byte) (op *AsyncOperation, err error) {
+	op = &AsyncOperation{}
+
+	if err = json.Unmarshal(data, op); err != nil {
+		return nil, err
+	}
+
+	if op.MonitorUrl == "" {
+		return nil, fmt.Errorf("async operation monitor url missing")
+	}
+
+	return op, nil
+}
+
+func (o *AsyncOperation) Marshal() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func (c *OneDrive) checkAsyncOperation(op *AsyncOperation) error {
+	if op.IsGraph != c.IsGraph {
+		return fmt.Errorf("async operation was created by a client with IsGraph=%t", op.IsGraph)
+	}
+
+	return nil
+}
+
+func (c *OneDrive) ItemsCopyOperation(ctx context.Context, address Address, body *ItemCopyBody) (op *AsyncOperation, err error) {
+	monitorUrl, err := c.ItemsCopy(ctx, address, body)
+	if err != nil {
+		return nil, err
+	}
+
+	op = &AsyncOperation{
+		MonitorUrl:           monitorUrl,
+		IsGraph:              c.IsGraph,
+		DriveId:              c.DriveId,
+		NameConflictBehavior: body.NameConflictBehavior,
+		CreatedAt:            time.Now(),
+	}
+
+	if body.ParentReference != nil && body.ParentReference.DriveId != "" {
+		op.DriveId = body.ParentReference.DriveId
+	}
+
+	if op.NameConflictBehavior == "" {
+		op.NameConflictBehavior = NameConflictBehaviorFail
+	}
+
+	return op, nil
+}
+
+func (c *OneDrive) AsyncOperationPoll(ctx context.Context, op *AsyncOperation) (status *AsyncOperationStatus, item *Item, err error) {
+	if err = c.checkAsyncOperation(op); err != nil {
+		return nil, nil, err
+	}
+
+	return c.itemsCopyStatus(ctx, op.MonitorUrl, op.DriveId)
+}
+
+func (c *OneDrive) AsyncOperationAwait(ctx context.Context, op *AsyncOperation, onProgress func(status *AsyncOperationStatus)) (item *Item, err error) {
+	if err = c.checkAsyncOperation(op); err != nil {
+		return nil, err
+	}
+
+	return c.itemsCopyAwait(ctx, op.MonitorUrl, op.DriveId, onProgress)
+}
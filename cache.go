@@ -0,0 +1,374 @@
+package onedriveclient
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCacheTTL = 1 * time.Minute
+)
+
+type cacheEntry struct {
+	item      *Item
+	fetchedAt time.Time
+}
+
+// CachedOneDrive memoises items resolved by path. Fresh entries are served
+// without a request, stale entries are revalidated using their eTag.
+type CachedOneDrive struct {
+	*OneDrive
+	TTL time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry
+	now     func() time.Time
+}
+
+func NewCachedOneDrive(client *OneDrive, ttl time.Duration) *CachedOneDrive {
+	return &CachedOneDrive{
+		OneDrive: client,
+		TTL:      ttl,
+		entries:  map[string]*cacheEntry{},
+		now:      time.Now,
+	}
+}
+
+func cacheKey(address Address) (key string, ok bool) {
+	prefix, pth, ok := address.splitPath()
+	if !ok {
+		return "", false
+	}
+
+	return prefix + ":" + strings.ToLower(NormalizePath(pth)), true
+}
+
+func cacheTreePrefix(key string) string {
+	if strings.HasSuffix(key, "/") {
+		return key
+	}
+
+	return key + "/"
+}
+
+func copyItem(item *Item) *Item {
+	itemCopy := *item
+	return &itemCopy
+}
+
+func (c *CachedOneDrive) get(key string) (entry *cacheEntry, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok = c.entries[key]
+
+	return entry, ok
+}
+
+func (c *CachedOneDrive) put(key string, item *Item) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = &cacheEntry{
+		item:      copyItem(item),
+		fetchedAt: c.now(),
+	}
+}
+
+func (c *CachedOneDrive) touch(key string, entry *cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// entries are replaced instead of updated, because they are read outside
+	// of the mutex
+	if c.entries[key] == entry {
+		c.entries[key] = &cacheEntry{
+			item:      entry.item,
+			fetchedAt: c.now(),
+		}
+	}
+}
+
+func (c *CachedOneDrive) Invalidate(address Address) {
+	c.invalidate(address, true)
+}
+
+func (c *CachedOneDrive) InvalidateAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]*cacheEntry{}
+}
+
+func (c *CachedOneDrive) invalidate(address Address, tree bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if key, ok := cacheKey(address); ok {
+		c.remove(key, tree)
+	} else if address.Type == AddressTypeId {
+		c.removeId(strings.TrimPrefix(address.Address, "/items/"), tree)
+	}
+}
+
+func (c *CachedOneDrive) invalidateItem(item *Item, tree bool) {
+	if item == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if key, ok := cacheKey(AddressFromItem(item, c.DriveId)); ok {
+		c.remove(key, tree)
+
+		// the item path is known, so descendants are covered by the path
+		tree = false
+	}
+
+	c.removeId(item.Id, tree)
+}
+
+func (c *CachedOneDrive) invalidateUpload(address Address, name string, item *Item) {
+	c.invalidate(address, false)
+	if address.Type == AddressTypeId {
+		if joined, joinErr := address.Join(name); joinErr == nil {
+			c.invalidate(joined, false)
+		}
+	}
+	c.invalidateItem(item, false)
+}
+
+func (c *CachedOneDrive) remove(key string, tree bool) {
+	delete(c.entries, key)
+
+	if tree {
+		prefix := cacheTreePrefix(key)
+
+		for k := range c.entries {
+			if strings.HasPrefix(k, prefix) {
+				delete(c.entries, k)
+			}
+		}
+	}
+}
+
+// removeId removes entries of the item with id. Descendants can only be
+// found through a cached entry of the item itself, so if tree is set and the
+// item is not cached the whole cache is cleared.
+func (c *CachedOneDrive) removeId(id string, tree bool) {
+	found := false
+
+	for key, entry := range c.entries {
+		if entry.item.Id == id {
+			c.remove(key, tree)
+			found = true
+		}
+	}
+
+	if tree && !found {
+		c.entries = map[string]*cacheEntry{}
+		return
+	}
+
+	if tree {
+		prefix := "/items/" + id + ":"
+
+		for key := range c.entries {
+			if strings.HasPrefix(key, prefix) {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
+
+func (c *CachedOneDrive) ItemsGet(ctx context.Context, address Address) (item *Item, err error) {
+	key, ok := cacheKey(address)
+	if !ok {
+		return c.OneDrive.ItemsGet(ctx, address)
+	}
+
+	entry, ok := c.get(key)
+
+	if ok && c.now().Sub(entry.fetchedAt) < c.TTL {
+		return copyItem(entry.item), nil
+	}
+
+	var cond *Precondition
+	if ok && entry.item.ETag != "" {
+		cond = IfNoneMatch(entry.item.ETag)
+	}
+
+	item, err = c.OneDrive.ItemsGetConditional(ctx, address, cond)
+	if err == ErrNotModified {
+		c.touch(key, entry)
+
+		return copyItem(entry.item), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(key, item)
+
+	return item, nil
+}
+
+func (c *CachedOneDrive) ResolveId(ctx context.Context, address Address) (id string, err error) {
+	if address.Type == AddressTypeId {
+		return strings.TrimPrefix(address.Address, "/items/"), nil
+	}
+
+	item, err := c.ItemsGet(ctx, address)
+	if err != nil {
+		return "", err
+	}
+
+	return item.Id, nil
+}
+
+func (c *CachedOneDrive) ItemsUpdate(ctx context.Context, address Address, itemUpdate *ItemUpdateBody) (item *Item, err error) {
+	return c.ItemsUpdateConditional(ctx, address, itemUpdate, nil)
+}
+
+func (c *CachedOneDrive) ItemsUpdateConditional(ctx context.Context, address Address, itemUpdate *ItemUpdateBody, cond *Precondition) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUpdateConditional(ctx, address, itemUpdate, cond)
+
+	c.invalidate(address, true)
+	c.invalidateItem(item, true)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsMove(ctx context.Context, src Address, dstParent *ItemReference, newName string, nameConflictBehavior string) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsMove(ctx, src, dstParent, newName, nameConflictBehavior)
+
+	c.invalidate(src, true)
+	c.invalidateItem(item, true)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsDelete(ctx context.Context, address Address) (err error) {
+	return c.ItemsDeleteConditional(ctx, address, nil)
+}
+
+func (c *CachedOneDrive) ItemsDeleteConditional(ctx context.Context, address Address, cond *Precondition) (err error) {
+	err = c.OneDrive.ItemsDeleteConditional(ctx, address, cond)
+
+	c.invalidate(address, true)
+
+	return err
+}
+
+func (c *CachedOneDrive) ItemsCreate(ctx context.Context, address Address, body *ItemCreateBody) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsCreate(ctx, address, body)
+
+	c.invalidate(address, false)
+	if joined, joinErr := address.Join(body.Name); joinErr == nil {
+		c.invalidate(joined, false)
+	}
+	c.invalidateItem(item, false)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUpload(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
+	return c.ItemsUploadConditional(ctx, address, name, nameConflictBehavior, content, size, nil)
+}
+
+func (c *CachedOneDrive) ItemsUploadConditional(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64, cond *Precondition) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadConditional(ctx, address, name, nameConflictBehavior, content, size, cond)
+
+	c.invalidateUpload(address, name, item)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUploadSimple(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadSimple(ctx, address, name, nameConflictBehavior, content, size)
+
+	c.invalidateUpload(address, name, item)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUploadSession(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadSession(ctx, address, name, nameConflictBehavior, content, size)
+
+	c.invalidateUpload(address, name, item)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUploadSessionFinish(ctx context.Context, uploadSession *UploadSession, content io.Reader, start int64, end int64, size int64) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadSessionFinish(ctx, uploadSession, content, start, end, size)
+
+	c.invalidateItem(item, false)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsCreatePath(ctx context.Context, pth string) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsCreatePath(ctx, pth)
+
+	c.invalidate(AddressPath(pth), false)
+	c.invalidateItem(item, false)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUploadResume(ctx context.Context, upload *ResumableUpload, content io.ReaderAt) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadResume(ctx, upload, content)
+
+	c.invalidateItem(item, false)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsUploadResumeSeeker(ctx context.Context, upload *ResumableUpload, content io.ReadSeeker) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsUploadResumeSeeker(ctx, upload, content)
+
+	c.invalidateItem(item, false)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) ItemsCopyStatus(ctx context.Context, monitorUrl string) (status *AsyncOperationStatus, item *Item, err error) {
+	status, item, err = c.OneDrive.ItemsCopyStatus(ctx, monitorUrl)
+
+	c.invalidateItem(item, true)
+
+	return status, item, err
+}
+
+func (c *CachedOneDrive) ItemsCopyAwait(ctx context.Context, monitorUrl string) (item *Item, err error) {
+	return c.ItemsCopyAwaitProgress(ctx, monitorUrl, nil)
+}
+
+func (c *CachedOneDrive) ItemsCopyAwaitProgress(ctx context.Context, monitorUrl string, onProgress func(status *AsyncOperationStatus)) (item *Item, err error) {
+	item, err = c.OneDrive.ItemsCopyAwaitProgress(ctx, monitorUrl, onProgress)
+
+	c.invalidateItem(item, true)
+
+	return item, err
+}
+
+func (c *CachedOneDrive) AsyncOperationPoll(ctx context.Context, op *AsyncOperation) (status *AsyncOperationStatus, item *Item, err error) {
+	status, item, err = c.OneDrive.AsyncOperationPoll(ctx, op)
+
+	c.invalidateItem(item, true)
+
+	return status, item, err
+}
+
+func (c *CachedOneDrive) AsyncOperationAwait(ctx context.Context, op *AsyncOperation, onProgress func(status *AsyncOperationStatus)) (item *Item, err error) {
+	item, err = c.OneDrive.AsyncOperationAwait(ctx, op, onProgress)
+
+	c.invalidateItem(item, true)
+
+	return item, err
+}
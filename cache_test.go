@@ -0,0 +1,199 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CachedOneDrive", func() {
+	var server *httptest.Server
+	var client *CachedOneDrive
+	var requests int
+	var notModified int
+	var eTag string
+	var now time.Time
+
+	BeforeEach(func() {
+		requests = 0
+		notModified = 0
+		eTag = "etag1"
+		now = time.Now()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			Expect(r.URL.Path).To(Equal("/drives/drive/root:/dir/file.txt:"))
+
+			if r.Header.Get("If-None-Match") == eTag {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&Item{Id: "file-id", Name: "file.txt", ETag: eTag})
+		}))
+
+		client = NewCachedOneDrive(newStandInOneDrive(server.URL, "drive"), time.Minute)
+		client.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should serve fresh entries from cache", func() {
+		id, err := client.ResolveId(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("file-id"))
+
+		item, err := client.ItemsGet(context.Background(), AddressPath("/Dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal("file-id"))
+
+		Expect(requests).To(Equal(1))
+	})
+
+	It("should revalidate stale entries with eTag", func() {
+		_, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(2 * time.Minute)
+
+		item, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.ETag).To(Equal("etag1"))
+		Expect(notModified).To(Equal(1))
+
+		now = now.Add(2 * time.Minute)
+		eTag = "etag2"
+
+		item, err = client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.ETag).To(Equal("etag2"))
+		Expect(requests).To(Equal(3))
+	})
+
+	It("should invalidate descendants", func() {
+		_, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		client.Invalidate(AddressPath("/dir"))
+
+		_, err = client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		client.Invalidate(AddressId("file-id"))
+
+		_, err = client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(requests).To(Equal(3))
+	})
+})
+
+var _ = Describe("CachedOneDrive invalidation", func() {
+	var server *httptest.Server
+	var client *CachedOneDrive
+	var mutex sync.Mutex
+	var gets int
+
+	BeforeEach(func() {
+		gets = 0
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			item := &Item{
+				Id:              "file-id",
+				Name:            "file.txt",
+				ETag:            "etag1",
+				ParentReference: &ItemReference{DriveId: "drive", Id: "dir-id", Path: "/drive/root:/dir"},
+			}
+
+			switch r.Method + " " + r.URL.Path {
+			case "GET /drives/drive/root:/dir/file.txt:":
+				gets++
+
+				if r.Header.Get("If-None-Match") == item.ETag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			case "PUT /drives/drive/items/dir-id:/file.txt:/content":
+				io.Copy(io.Discard, r.Body)
+			default:
+				Fail("unexpected request: " + r.Method + " " + r.URL.Path)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(item)
+		}))
+
+		client = NewCachedOneDrive(newStandInOneDrive(server.URL, "drive"), time.Minute)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should invalidate everything for an uncached id tree", func() {
+		_, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		client.Invalidate(AddressId("dir-id"))
+
+		_, err = client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(gets).To(Equal(2))
+	})
+
+	It("should invalidate uploaded items", func() {
+		_, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.ItemsUploadSimple(context.Background(), AddressId("dir-id"), "file.txt", NameConflictBehaviorReplace, strings.NewReader("12345"), 5)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(gets).To(Equal(2))
+	})
+
+	It("should be safe for concurrent revalidation", func() {
+		client.TTL = 0
+
+		_, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for j := 0; j < 10; j++ {
+					item, err := client.ItemsGet(context.Background(), AddressPath("/dir/file.txt"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(item.Id).To(Equal("file-id"))
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+})
@@ -0,0 +1,178 @@
+package onedriveclient
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/koofr/go-ioutils"
+)
+
+// ContentRange is a byte range of item content. A negative End means "until
+// the end of the content", a positive Suffix requests the last Suffix bytes
+// and ignores Start and End.
+type ContentRange struct {
+	Start  int64
+	End    int64
+	Suffix int64
+
+	// suffix marks ranges created by RangeSuffix, so that a zero length is
+	// rejected instead of being sent as the first byte
+	suffix bool
+}
+
+func RangeSpan(start int64, end int64) *ContentRange {
+	return &ContentRange{Start: start, End: end}
+}
+
+func RangeFrom(start int64) *ContentRange {
+	return &ContentRange{Start: start, End: -1}
+}
+
+func RangeSuffix(length int64) *ContentRange {
+	return &ContentRange{Suffix: length, suffix: true}
+}
+
+func RangeFromFileSpan(span *ioutils.FileSpan) *ContentRange {
+	if span == nil {
+		return nil
+	}
+
+	return RangeSpan(span.Start, span.End)
+}
+
+func (r *ContentRange) validate() error {
+	if r.suffix || r.Suffix != 0 {
+		if r.Suffix <= 0 {
+			return fmt.Errorf("invalid suffix range length %d", r.Suffix)
+		}
+
+		return nil
+	}
+
+	if r.Start < 0 || (r.End >= 0 && r.End < r.Start) {
+		return fmt.Errorf("invalid range %d-%d", r.Start, r.End)
+	}
+
+	return nil
+}
+
+func (r *ContentRange) header() string {
+	if r.Suffix > 0 {
+		return fmt.Sprintf("bytes=-%d", r.Suffix)
+	}
+
+	if r.End < 0 {
+		return fmt.Sprintf("bytes=%d-", r.Start)
+	}
+
+	return fmt.Sprintf("bytes=%d-%d", r.Start, r.End)
+}
+
+// ContentInfo describes a content response. Start and End are the returned
+// byte positions and Total is the full content size, or -1 if unknown.
+// Partial is set if the server returned a range, otherwise the response
+// contains the whole content.
+type ContentInfo struct {
+	Start   int64
+	End     int64
+	Size    int64
+	Total   int64
+	Partial bool
+	ETag    string
+	Header  http.Header
+}
+
+type RangeNotSatisfiableError struct {
+	Range *ContentRange
+	Total int64
+}
+
+func (e *RangeNotSatisfiableError) Error() string {
+	rng := "bytes=*"
+	if e.Range != nil {
+		rng = e.Range.header()
+	}
+
+	if e.Total >= 0 {
+		return fmt.Sprintf("range not satisfiable: %s (size %d)", rng, e.Total)
+	}
+
+	return fmt.Sprintf("range not satisfiable: %s", rng)
+}
+
+func IsRangeNotSatisfiableError(err error) (rangeErr *RangeNotSatisfiableError, ok bool) {
+	if rne, ok := err.(*RangeNotSatisfiableError); ok {
+		return rne, true
+	} else {
+		return nil, false
+	}
+}
+
+// parseContentRange parses "bytes start-end/total" and "bytes */total".
+func parseContentRange(value string) (start int64, end int64, total int64, ok bool) {
+	value = strings.TrimSpace(value)
+
+	if !strings.HasPrefix(value, "bytes ") {
+		return 0, 0, 0, false
+	}
+
+	rng, totalStr, found := strings.Cut(strings.TrimPrefix(value, "bytes "), "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+
+	total = -1
+	if totalStr != "*" {
+		t, err := strconv.ParseInt(totalStr, 10, 64)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		total = t
+	}
+
+	if rng == "*" {
+		return 0, -1, total, true
+	}
+
+	startStr, endStr, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+
+	end, err = strconv.ParseInt(endStr, 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+
+	return start, end, total, true
+}
+
+func contentInfoFromResponse(res *http.Response) *ContentInfo {
+	info := &ContentInfo{
+		Size:   res.ContentLength,
+		Total:  -1,
+		ETag:   res.Header.Get("ETag"),
+		Header: res.Header,
+	}
+
+	if res.StatusCode == http.StatusPartialContent {
+		if start, end, total, ok := parseContentRange(res.Header.Get("Content-Range")); ok {
+			info.Start = start
+			info.End = end
+			info.Total = total
+			info.Partial = true
+		}
+	} else if res.ContentLength >= 0 {
+		info.End = res.ContentLength - 1
+		info.Total = res.ContentLength
+	}
+
+	return info
+}
@@ -0,0 +1,108 @@
+package onedriveclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContentRange", func() {
+	It("should format range headers", func() {
+		Expect(RangeSpan(2, 5).header()).To(Equal("bytes=2-5"))
+		Expect(RangeFrom(7).header()).To(Equal("bytes=7-"))
+		Expect(RangeSuffix(3).header()).To(Equal("bytes=-3"))
+	})
+
+	It("should parse Content-Range", func() {
+		start, end, total, ok := parseContentRange("bytes 2-5/36")
+		Expect(ok).To(BeTrue())
+		Expect([]int64{start, end, total}).To(Equal([]int64{2, 5, 36}))
+
+		_, _, total, ok = parseContentRange("bytes */36")
+		Expect(ok).To(BeTrue())
+		Expect(total).To(Equal(int64(36)))
+
+		_, _, total, ok = parseContentRange("bytes 2-5/*")
+		Expect(ok).To(BeTrue())
+		Expect(total).To(Equal(int64(-1)))
+
+		_, _, _, ok = parseContentRange("items 2-5/36")
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("ItemsContentRange", func() {
+		var server *httptest.Server
+		var client *OneDrive
+
+		content := "0123456789abcdefghijklmnopqrstuvwxyz"
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+			}))
+
+			client = newStandInOneDrive(server.URL, "drive")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		get := func(rng *ContentRange) (string, *ContentInfo) {
+			reader, info, err := client.ItemsContentRange(context.Background(), AddressId("file"), rng, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			return string(data), info
+		}
+
+		It("should get open-ended ranges", func() {
+			data, info := get(RangeFrom(30))
+			Expect(data).To(Equal("uvwxyz"))
+			Expect(info.Start).To(Equal(int64(30)))
+			Expect(info.End).To(Equal(int64(35)))
+			Expect(info.Size).To(Equal(int64(6)))
+			Expect(info.Total).To(Equal(int64(36)))
+		})
+
+		It("should get suffix ranges", func() {
+			data, info := get(RangeSuffix(4))
+			Expect(data).To(Equal("wxyz"))
+			Expect(info.Start).To(Equal(int64(32)))
+			Expect(info.Total).To(Equal(int64(36)))
+		})
+
+		It("should get whole content", func() {
+			data, info := get(nil)
+			Expect(data).To(Equal(content))
+			Expect(info.Start).To(Equal(int64(0)))
+			Expect(info.Total).To(Equal(int64(36)))
+		})
+
+		It("should reject invalid ranges before any request", func() {
+			for _, rng := range []*ContentRange{RangeSuffix(0), RangeSuffix(-1), RangeSpan(5, 2), RangeFrom(-1)} {
+				_, _, err := client.ItemsContentRange(context.Background(), AddressId("file"), rng, nil)
+				Expect(err).To(HaveOccurred())
+			}
+
+			Expect(RangeSpan(0, 0).validate()).To(Succeed())
+		})
+
+		It("should return typed error for unsatisfiable ranges", func() {
+			_, _, err := client.ItemsContentRange(context.Background(), AddressId("file"), RangeFrom(100), nil)
+
+			rangeErr, ok := IsRangeNotSatisfiableError(err)
+			Expect(ok).To(BeTrue())
+			Expect(rangeErr.Total).To(Equal(int64(36)))
+		})
+	})
+})
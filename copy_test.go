@@ -0,0 +1,219 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copy", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var statuses []*AsyncOperationStatus
+	var polls int
+	var copyQuery url.Values
+	var copyBody *ItemCopyBody
+
+	BeforeEach(func() {
+		polls = 0
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/drives/drive/items/src-id/copy" {
+				copyQuery = r.URL.Query()
+				copyBody = &ItemCopyBody{}
+				json.NewDecoder(r.Body).Decode(copyBody)
+
+				w.Header().Set("Location", server.URL+"/monitor")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			if strings.HasPrefix(r.URL.Path, "/drives/") && strings.HasSuffix(r.URL.Path, "/items/copy-id") {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				driveId := strings.Split(r.URL.Path, "/")[2]
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "copy-id", Name: "file copy.txt", ParentReference: &ItemReference{DriveId: driveId}})
+				return
+			}
+
+			status := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				status = statuses[polls]
+			}
+			polls++
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(status)
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.CopyPollInterval = time.Millisecond
+		client.CopyMaxPollInterval = 4 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("ItemsCopy", func() {
+		It("should send conflict behavior", func() {
+			_, err := client.ItemsCopy(context.Background(), AddressId("src-id"), &ItemCopyBody{
+				Name:                 "file.txt",
+				NameConflictBehavior: NameConflictBehaviorRename,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(copyQuery.Get("@microsoft.graph.conflictBehavior")).To(Equal(NameConflictBehaviorRename))
+		})
+
+		It("should target the client drive by default", func() {
+			_, err := client.ItemsCopy(context.Background(), AddressId("src-id"), &ItemCopyBody{
+				ParentReference: &ItemReference{Id: "dest-id"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(copyBody.ParentReference.DriveId).To(Equal("drive"))
+		})
+
+		It("should copy to another drive", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusCompleted, PercentageComplete: 100, ResourceId: "copy-id"},
+			}
+
+			op, err := client.ItemsCopyOperation(context.Background(), AddressId("src-id"), &ItemCopyBody{
+				ParentReference: &ItemReference{
+					DriveId: "other",
+					Id:      "dest-id",
+				},
+				NameConflictBehavior: NameConflictBehaviorReplace,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(copyBody.ParentReference.DriveId).To(Equal("other"))
+			Expect(op.DriveId).To(Equal("other"))
+			Expect(op.NameConflictBehavior).To(Equal(NameConflictBehaviorReplace))
+
+			item, err := client.AsyncOperationAwait(context.Background(), op, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.ParentReference.DriveId).To(Equal("other"))
+		})
+	})
+
+	Describe("ItemsCopyAwaitProgress", func() {
+		It("should report progress and fail with status", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusInProgress, PercentageComplete: 10},
+				{Status: AsyncOperationStatusInProgress, PercentageComplete: 50},
+				{Status: AsyncOperationStatusFailed, PercentageComplete: 50, ErrorCode: "quotaLimitReached"},
+			}
+
+			progress := []float64{}
+
+			_, err := client.ItemsCopyAwaitProgress(context.Background(), server.URL+"/monitor", func(status *AsyncOperationStatus) {
+				progress = append(progress, status.PercentageComplete)
+			})
+
+			Expect(progress).To(Equal([]float64{10, 50, 50}))
+
+			failedErr, ok := err.(*AsyncOperationFailedError)
+			Expect(ok).To(BeTrue())
+			Expect(failedErr.Status.ErrorCode).To(Equal("quotaLimitReached"))
+		})
+
+		It("should resolve the copied item on completion", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusInProgress, PercentageComplete: 10},
+				{Status: AsyncOperationStatusCompleted, PercentageComplete: 100, ResourceId: "copy-id"},
+			}
+
+			item, err := client.ItemsCopyAwait(context.Background(), server.URL+"/monitor")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Id).To(Equal("copy-id"))
+		})
+
+		It("should resolve the copied item by resource location", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusCompleted, PercentageComplete: 100, ResourceLocation: server.URL + "/drives/drive/items/copy-id"},
+			}
+
+			item, err := client.ItemsCopyAwait(context.Background(), server.URL+"/monitor")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Name).To(Equal("file copy.txt"))
+		})
+
+		It("should time out", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusInProgress},
+			}
+
+			client.CopyTimeout = 20 * time.Millisecond
+
+			_, err := client.ItemsCopyAwait(context.Background(), server.URL+"/monitor")
+			Expect(err).To(Equal(ErrCopyTimeout))
+		})
+
+		It("should stop when context is canceled", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusInProgress},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			_, err := client.ItemsCopyAwaitProgress(ctx, server.URL+"/monitor", func(status *AsyncOperationStatus) {
+				cancel()
+			})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+
+	Describe("AsyncOperation", func() {
+		It("should resume a serialized operation", func() {
+			statuses = []*AsyncOperationStatus{
+				{Status: AsyncOperationStatusCompleted, PercentageComplete: 100, ResourceId: "copy-id"},
+			}
+
+			op := &AsyncOperation{
+				MonitorUrl: server.URL + "/monitor",
+				IsGraph:    true,
+				DriveId:    "drive",
+			}
+
+			data, err := op.Marshal()
+			Expect(err).NotTo(HaveOccurred())
+
+			resumed, err := ParseAsyncOperation(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resumed.MonitorUrl).To(Equal(op.MonitorUrl))
+
+			other := newStandInOneDrive(server.URL, "other")
+			other.CopyPollInterval = time.Millisecond
+
+			item, err := other.AsyncOperationAwait(context.Background(), resumed, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Id).To(Equal("copy-id"))
+		})
+
+		It("should not resume an operation of a different client kind", func() {
+			op := &AsyncOperation{
+				MonitorUrl: server.URL + "/monitor",
+				IsGraph:    false,
+			}
+
+			_, _, err := client.AsyncOperationPoll(context.Background(), op)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package onedriveclient
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemsCreatePath", func() {
+	var server *httptest.Server
+	var tree *standInTree
+	var client *OneDrive
+
+	BeforeEach(func() {
+		tree = newStandInTree()
+		server = httptest.NewServer(tree)
+		client = newStandInOneDrive(server.URL, "drive")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create missing folders", func() {
+		existing := tree.add("/a", &Folder{})
+
+		item, err := client.ItemsCreatePath(context.Background(), "/a/b/c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("c"))
+
+		Expect(tree.paths).To(HaveKey("/a/b"))
+		Expect(tree.paths["/a"]).To(Equal(existing.Id))
+		Expect(tree.paths["/a/b/c"]).To(Equal(item.Id))
+	})
+
+	It("should return existing folder", func() {
+		existing := tree.add("/a", &Folder{})
+
+		item, err := client.ItemsCreatePath(context.Background(), "a/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal(existing.Id))
+		Expect(tree.requests).To(HaveLen(1))
+	})
+
+	It("should reuse folders created concurrently", func() {
+		var concurrent *Item
+
+		tree.beforeCreate = func(pth string) {
+			if pth == "/a" {
+				concurrent = tree.add(pth, &Folder{})
+			}
+		}
+
+		item, err := client.ItemsCreatePath(context.Background(), "/a/b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("b"))
+		Expect(tree.paths["/a"]).To(Equal(concurrent.Id))
+	})
+
+	It("should fail if a segment is a file", func() {
+		tree.add("/a", nil)
+
+		_, err := client.ItemsCreatePath(context.Background(), "/a/b")
+		Expect(err).To(HaveOccurred())
+	})
+})
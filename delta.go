@@ -0,0 +1,138 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// DeltaCursorLatest starts a DeltaSession from the current state of the drive
+// without enumerating existing items. The first Sync only yields a cursor.
+const DeltaCursorLatest = "latest"
+
+// deltaTokenLatest is the delta token the API accepts for the current state.
+const deltaTokenLatest = "latest"
+
+type deltaCursor struct {
+	DeltaLink string `json:"deltaLink,omitempty"`
+	Token     string `json:"token,omitempty"`
+}
+
+func encodeDeltaCursor(cur *deltaCursor) (cursor string, err error) {
+	buf, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeDeltaCursor(cursor string) (cur *deltaCursor, err error) {
+	cur = &deltaCursor{}
+
+	if cursor == "" {
+		return cur, nil
+	}
+
+	if cursor == DeltaCursorLatest {
+		cur.Token = deltaTokenLatest
+		return cur, nil
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delta cursor: %w", err)
+	}
+
+	if err = json.Unmarshal(buf, cur); err != nil {
+		return nil, fmt.Errorf("invalid delta cursor: %w", err)
+	}
+
+	return cur, nil
+}
+
+// DeltaSession iterates ItemsDelta pages for an address. Its cursor is an
+// opaque string that hides the difference between legacy delta tokens and
+// Graph delta links. An empty cursor enumerates the whole tree.
+type DeltaSession struct {
+	client  *OneDrive
+	address Address
+	cursor  string
+}
+
+func (c *OneDrive) NewDeltaSession(address Address, cursor string) *DeltaSession {
+	return &DeltaSession{
+		client:  c,
+		address: address,
+		cursor:  cursor,
+	}
+}
+
+func (s *DeltaSession) Cursor() string {
+	return s.cursor
+}
+
+func (s *DeltaSession) Reset() {
+	s.cursor = ""
+}
+
+// Sync fetches all pages of changes since the session cursor and passes them
+// to onChanges in order. The new cursor is returned and stored in the session
+// only after every page has been handled without error. Errors for which
+// IsErrorResync is true mean the cursor is no longer valid and the session
+// has to be Reset.
+func (s *DeltaSession) Sync(ctx context.Context, onChanges func(items []*Item) error) (cursor string, err error) {
+	cur, err := decodeDeltaCursor(s.cursor)
+	if err != nil {
+		return "", err
+	}
+
+	link := cur.DeltaLink
+	token := cur.Token
+
+	if link != "" {
+		token = ""
+	}
+
+	for {
+		page, err := s.client.ItemsDelta(ctx, s.address, link, token)
+		if err != nil {
+			return "", err
+		}
+
+		if len(page.Value) > 0 {
+			if err = onChanges(page.Value); err != nil {
+				return "", err
+			}
+		}
+
+		if page.NextLink != "" {
+			link = page.NextLink
+			token = ""
+			continue
+		}
+
+		if page.DeltaLink == "" && page.Token == "" {
+			return "", fmt.Errorf("delta page has no next link, delta link or token")
+		}
+
+		cursor, err = encodeDeltaCursor(&deltaCursor{
+			DeltaLink: page.DeltaLink,
+			Token:     page.Token,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		s.cursor = cursor
+
+		return cursor, nil
+	}
+}
+
+func (c *OneDrive) ItemsDeltaLatest(ctx context.Context, address Address) (cursor string, err error) {
+	return c.NewDeltaSession(address, DeltaCursorLatest).Sync(ctx, func(items []*Item) error {
+		return nil
+	})
+}
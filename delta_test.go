@@ -0,0 +1,124 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeltaSession", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var requests []string
+
+	BeforeEach(func() {
+		requests = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RequestURI())
+
+			page := &DeltaCollectionPage{}
+
+			switch r.URL.RequestURI() {
+			case "/drives/drive/items/root/delta":
+				page.Value = []*Item{{Id: "root"}, {Id: "a"}}
+				page.NextLink = server.URL + "/page2"
+			case "/page2":
+				page.Value = []*Item{{Id: "b"}}
+				page.DeltaLink = server.URL + "/delta?token=t1"
+			case "/drives/drive/items/root/delta?token=latest":
+				page.DeltaLink = server.URL + "/delta?token=t1"
+			case "/delta?token=t1":
+				page.Value = []*Item{{Id: "c"}}
+				page.DeltaLink = server.URL + "/delta?token=t2"
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should iterate all pages and resume from cursor", func() {
+		session := client.NewDeltaSession(AddressRoot, "")
+
+		ids := []string{}
+		onChanges := func(items []*Item) error {
+			for _, item := range items {
+				ids = append(ids, item.Id)
+			}
+			return nil
+		}
+
+		cursor, err := session.Sync(context.Background(), onChanges)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(Equal([]string{"root", "a", "b"}))
+		Expect(session.Cursor()).To(Equal(cursor))
+
+		ids = nil
+
+		resumed := client.NewDeltaSession(AddressRoot, cursor)
+
+		_, err = resumed.Sync(context.Background(), onChanges)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(Equal([]string{"c"}))
+	})
+
+	It("should not advance cursor if a page fails", func() {
+		session := client.NewDeltaSession(AddressRoot, "")
+
+		_, err := session.Sync(context.Background(), func(items []*Item) error {
+			if items[0].Id == "b" {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(session.Cursor()).To(Equal(""))
+	})
+
+	It("should start from latest", func() {
+		cursor, err := client.ItemsDeltaLatest(context.Background(), AddressRoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		ids := []string{}
+
+		session := client.NewDeltaSession(AddressRoot, cursor)
+
+		_, err = session.Sync(context.Background(), func(items []*Item) error {
+			for _, item := range items {
+				ids = append(ids, item.Id)
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(Equal([]string{"c"}))
+	})
+
+	It("should start a session from latest", func() {
+		session := client.NewDeltaSession(AddressRoot, DeltaCursorLatest)
+
+		called := false
+
+		_, err := session.Sync(context.Background(), func(items []*Item) error {
+			called = true
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(called).To(BeFalse())
+		Expect(requests).To(Equal([]string{"/drives/drive/items/root/delta?token=latest"}))
+	})
+})
@@ -0,0 +1,231 @@
+package onedriveclient
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+type DeltaPathNode struct {
+	Name     string `json:"name"`
+	ParentId string `json:"parentId,omitempty"`
+}
+
+type DeltaPathSnapshot struct {
+	RootId string                    `json:"rootId"`
+	Nodes  map[string]*DeltaPathNode `json:"nodes"`
+}
+
+// DeltaPathChange is an item from a delta page with its resolved path.
+// OldPath is the path known before the change and is empty for new items.
+// Path is empty if the item could not be resolved to the root.
+type DeltaPathChange struct {
+	Item    *Item
+	Path    string
+	OldPath string
+	Deleted bool
+}
+
+// DeltaPathIndex keeps an id to (name, parent id) index built from delta
+// pages so that paths can be resolved even when parentReference.path is
+// missing, as it is for OneDrive for Business.
+type DeltaPathIndex struct {
+	mutex  sync.RWMutex
+	rootId string
+	nodes  map[string]*DeltaPathNode
+}
+
+func NewDeltaPathIndex() *DeltaPathIndex {
+	return &DeltaPathIndex{
+		nodes: map[string]*DeltaPathNode{},
+	}
+}
+
+func NewDeltaPathIndexFromSnapshot(snapshot *DeltaPathSnapshot) *DeltaPathIndex {
+	x := NewDeltaPathIndex()
+
+	x.rootId = snapshot.RootId
+
+	for id, node := range snapshot.Nodes {
+		nodeCopy := *node
+		x.nodes[id] = &nodeCopy
+	}
+
+	return x
+}
+
+func (x *DeltaPathIndex) Snapshot() *DeltaPathSnapshot {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	snapshot := &DeltaPathSnapshot{
+		RootId: x.rootId,
+		Nodes:  make(map[string]*DeltaPathNode, len(x.nodes)),
+	}
+
+	for id, node := range x.nodes {
+		nodeCopy := *node
+		snapshot.Nodes[id] = &nodeCopy
+	}
+
+	return snapshot
+}
+
+func (x *DeltaPathIndex) Has(id string) bool {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	_, ok := x.nodes[id]
+
+	return ok
+}
+
+func (x *DeltaPathIndex) Path(id string) (pth string, ok bool) {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	return x.path(id)
+}
+
+func (x *DeltaPathIndex) path(id string) (pth string, ok bool) {
+	names := []string{}
+
+	for i := 0; i <= len(x.nodes); i++ {
+		if id != "" && id == x.rootId {
+			for l, r := 0, len(names)-1; l < r; l, r = l+1, r-1 {
+				names[l], names[r] = names[r], names[l]
+			}
+
+			return "/" + strings.Join(names, "/"), true
+		}
+
+		node, ok := x.nodes[id]
+		if !ok {
+			return "", false
+		}
+
+		names = append(names, node.Name)
+		id = node.ParentId
+	}
+
+	// parent ids form a cycle
+	return "", false
+}
+
+// isRoot reports whether item is the root of paths. Once the root id is known
+// (from the first root item or a snapshot) only that item is the root.
+func (x *DeltaPathIndex) isRoot(item *Item) bool {
+	if x.rootId != "" {
+		return item.Id == x.rootId
+	}
+
+	return item.Root != nil || (item.ParentReference == nil && item.Name == "root")
+}
+
+// Apply updates the index with items from a delta page and returns the
+// changes with resolved paths. Removing a folder also removes everything
+// below it from the index.
+func (x *DeltaPathIndex) Apply(items []*Item) (changes []*DeltaPathChange) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	oldPaths := make(map[string]string, len(items))
+
+	for _, item := range items {
+		if oldPath, ok := x.path(item.Id); ok {
+			oldPaths[item.Id] = oldPath
+		}
+	}
+
+	deleted := map[string]bool{}
+
+	for _, item := range items {
+		if item.Deleted != nil {
+			deleted[item.Id] = true
+			continue
+		}
+
+		if x.isRoot(item) {
+			x.rootId = item.Id
+			x.nodes[item.Id] = &DeltaPathNode{}
+			continue
+		}
+
+		node := &DeltaPathNode{
+			Name: item.Name,
+		}
+
+		if item.ParentReference != nil {
+			node.ParentId = item.ParentReference.Id
+		}
+
+		x.nodes[item.Id] = node
+	}
+
+	changes = make([]*DeltaPathChange, 0, len(items))
+
+	for _, item := range items {
+		change := &DeltaPathChange{
+			Item:    item,
+			OldPath: oldPaths[item.Id],
+			Deleted: deleted[item.Id],
+		}
+
+		if change.Deleted {
+			change.Path = change.OldPath
+
+			if change.Path == "" && item.ParentReference != nil && item.Name != "" {
+				if parentPath, ok := x.path(item.ParentReference.Id); ok {
+					change.Path = path.Join(parentPath, item.Name)
+				}
+			}
+		} else {
+			change.Path, _ = x.path(item.Id)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if len(deleted) > 0 {
+		x.removeDeleted(deleted)
+	}
+
+	return changes
+}
+
+func (x *DeltaPathIndex) removeDeleted(deleted map[string]bool) {
+	removed := map[string]bool{}
+
+	for id := range x.nodes {
+		current := id
+
+		for i := 0; i <= len(x.nodes); i++ {
+			if deleted[current] || removed[current] {
+				removed[id] = true
+				break
+			}
+
+			node, ok := x.nodes[current]
+			if !ok {
+				break
+			}
+
+			current = node.ParentId
+		}
+	}
+
+	for id := range removed {
+		delete(x.nodes, id)
+	}
+
+	for id := range deleted {
+		delete(x.nodes, id)
+	}
+}
+
+// Consumer adapts the index for use with DeltaSession.Sync.
+func (x *DeltaPathIndex) Consumer(onChanges func(changes []*DeltaPathChange) error) func(items []*Item) error {
+	return func(items []*Item) error {
+		return onChanges(x.Apply(items))
+	}
+}
@@ -0,0 +1,103 @@
+package onedriveclient
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeltaPathIndex", func() {
+	var index *DeltaPathIndex
+
+	item := func(id string, name string, parentId string) *Item {
+		return &Item{
+			Id:              id,
+			Name:            name,
+			ParentReference: &ItemReference{Id: parentId},
+		}
+	}
+
+	deleted := func(id string) *Item {
+		return &Item{
+			Id:      id,
+			Deleted: &Deleted{},
+		}
+	}
+
+	paths := func(changes []*DeltaPathChange) []string {
+		result := []string{}
+		for _, change := range changes {
+			result = append(result, change.OldPath+" -> "+change.Path)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		index = NewDeltaPathIndex()
+
+		index.Apply([]*Item{
+			{Id: "root", Name: "root", Root: &Root{}},
+			item("a", "a", "root"),
+			item("b", "b", "a"),
+			item("c", "c.txt", "b"),
+		})
+	})
+
+	It("should resolve paths", func() {
+		pth, ok := index.Path("c")
+		Expect(ok).To(BeTrue())
+		Expect(pth).To(Equal("/a/b/c.txt"))
+
+		pth, ok = index.Path("root")
+		Expect(ok).To(BeTrue())
+		Expect(pth).To(Equal("/"))
+	})
+
+	It("should resolve children listed before their parents", func() {
+		changes := index.Apply([]*Item{
+			item("e", "e.txt", "d"),
+			item("d", "d", "root"),
+		})
+
+		Expect(paths(changes)).To(Equal([]string{" -> /d/e.txt", " -> /d"}))
+	})
+
+	It("should handle renames and moves of ancestors", func() {
+		changes := index.Apply([]*Item{
+			item("a", "renamed", "root"),
+			item("b", "b", "root"),
+		})
+
+		Expect(paths(changes)).To(Equal([]string{"/a -> /renamed", "/a/b -> /b"}))
+
+		pth, _ := index.Path("c")
+		Expect(pth).To(Equal("/b/c.txt"))
+	})
+
+	It("should handle deletions of ancestors", func() {
+		changes := index.Apply([]*Item{
+			deleted("c"),
+			deleted("a"),
+		})
+
+		Expect(paths(changes)).To(Equal([]string{"/a/b/c.txt -> /a/b/c.txt", "/a -> /a"}))
+		Expect(changes[0].Deleted).To(BeTrue())
+
+		Expect(index.Has("a")).To(BeFalse())
+		Expect(index.Has("b")).To(BeFalse())
+		Expect(index.Has("c")).To(BeFalse())
+		Expect(index.Has("root")).To(BeTrue())
+	})
+
+	It("should be seeded from a snapshot", func() {
+		seeded := NewDeltaPathIndexFromSnapshot(index.Snapshot())
+
+		changes := seeded.Apply([]*Item{
+			item("c", "c.txt", "a"),
+		})
+
+		Expect(paths(changes)).To(Equal([]string{"/a/b/c.txt -> /a/c.txt"}))
+
+		pth, _ := index.Path("c")
+		Expect(pth).To(Equal("/a/b/c.txt"))
+	})
+})
@@ -0,0 +1,202 @@
+package onedriveclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type downloadRange struct {
+	start int64
+	end   int64
+}
+
+type downloadProgress struct {
+	mutex      sync.Mutex
+	downloaded int64
+	total      int64
+	onProgress func(downloaded int64, total int64)
+}
+
+func (p *downloadProgress) add(n int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.downloaded += n
+
+	if p.onProgress != nil {
+		p.onProgress(p.downloaded, p.total)
+	}
+}
+
+type downloadRangeWriter struct {
+	w        io.WriterAt
+	offset   int64
+	progress *downloadProgress
+}
+
+func (w *downloadRangeWriter) Write(p []byte) (n int, err error) {
+	n, err = w.w.WriteAt(p, w.offset)
+
+	w.offset += int64(n)
+	w.progress.add(int64(n))
+
+	return n, err
+}
+
+// ItemsDownload downloads the item at address into w, fetching
+// DownloadChunkSize ranges with DownloadParallelism concurrent requests.
+// Failed ranges are retried individually, continuing from the last byte
+// written. All ranges are pinned to the item eTag. If VerifyHashes is set and
+// w is also an io.ReaderAt, the written content is verified.
+func (c *OneDrive) ItemsDownload(ctx context.Context, address Address, w io.WriterAt, onProgress func(downloaded int64, total int64)) (item *Item, err error) {
+	item, err = c.ItemsGet(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.ItemsDownloadItem(ctx, item, w, onProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (c *OneDrive) ItemsDownloadItem(ctx context.Context, item *Item, w io.WriterAt, onProgress func(downloaded int64, total int64)) (err error) {
+	chunkSize := c.DownloadChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultDownloadChunkSize
+	}
+
+	parallelism := c.DownloadParallelism
+	if parallelism <= 0 {
+		parallelism = DefaultDownloadParallelism
+	}
+
+	ranges := make(chan downloadRange)
+
+	go func() {
+		defer close(ranges)
+
+		for start := int64(0); start < item.Size; start += chunkSize {
+			end := start + chunkSize - 1
+			if end >= item.Size {
+				end = item.Size - 1
+			}
+
+			select {
+			case ranges <- downloadRange{start: start, end: end}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	downloadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reader := c.newItemReader(downloadCtx, item, false)
+
+	progress := &downloadProgress{
+		total:      item.Size,
+		onProgress: onProgress,
+	}
+
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for r := range ranges {
+				if downloadCtx.Err() != nil {
+					continue
+				}
+
+				if rangeErr := c.downloadRange(downloadCtx, reader, r, w, progress); rangeErr != nil {
+					errOnce.Do(func() {
+						err = rangeErr
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err == nil && c.VerifyHashes {
+		if ra, ok := w.(io.ReaderAt); ok {
+			err = VerifyItemHash(item, io.NewSectionReader(ra, 0, item.Size))
+		}
+	}
+
+	return err
+}
+
+func (c *OneDrive) downloadRange(ctx context.Context, reader *ItemReader, r downloadRange, w io.WriterAt, progress *downloadProgress) (err error) {
+	rw := &downloadRangeWriter{
+		w:        w,
+		offset:   r.start,
+		progress: progress,
+	}
+
+	delay := c.DownloadRetryDelay
+
+	for attempt := 0; ; attempt++ {
+		err = c.downloadRangeAttempt(reader, rw, r.end)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !isRetryableError(err) || attempt >= c.DownloadRetries {
+			return err
+		}
+
+		wait := delay
+		if retryAfter, ok := RetryAfter(err); ok {
+			wait = retryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+	}
+}
+
+// downloadRangeAttempt writes content from rw.offset to end. reader.open
+// rejects responses that don't start at rw.offset, so a server ignoring Range
+// can't write misplaced data.
+func (c *OneDrive) downloadRangeAttempt(reader *ItemReader, rw *downloadRangeWriter, end int64) (err error) {
+	body, err := reader.open(rw.offset, end)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	expected := end - rw.offset + 1
+
+	n, err := io.Copy(rw, io.LimitReader(body, expected))
+	if err != nil {
+		return err
+	}
+
+	if n < expected {
+		return io.ErrUnexpectedEOF
+	}
+
+	return nil
+}
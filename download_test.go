@@ -0,0 +1,173 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type memoryWriterAt struct {
+	mutex sync.Mutex
+	data  []byte
+}
+
+func (m *memoryWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	copy(m.data[off:], p)
+
+	return len(p), nil
+}
+
+var _ = Describe("ItemsDownload", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var content string
+	var mutex sync.Mutex
+	var ranges []string
+	var failed map[string]bool
+	var ignoreRange bool
+	var missing bool
+
+	BeforeEach(func() {
+		content = "0123456789abcdefghijklmnopqrstuvwxyz"
+		ranges = nil
+		failed = map[string]bool{}
+		ignoreRange = false
+		missing = false
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			rng := r.Header.Get("Range")
+			ranges = append(ranges, rng)
+			fail := !failed[rng]
+			failed[rng] = true
+			mutex.Unlock()
+
+			switch {
+			case r.URL.Path == "/drives/drive/items/file":
+				h := NewItemHasher()
+				h.Write([]byte(content))
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "file", ETag: `"etag1"`, Size: 36, File: &File{Hashes: h.Hashes()}})
+				return
+			case r.URL.Path != "/drives/drive/items/file/content":
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case missing:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case ignoreRange:
+				w.Header().Set("ETag", `"etag1"`)
+				w.Write([]byte(content))
+				return
+			case rng == "bytes=10-19" && fail:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			case rng == "bytes=20-29" && fail:
+				w.Header().Set("Content-Range", "bytes 20-29/36")
+				w.Header().Set("Content-Length", "10")
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte(content[20:23]))
+				return
+			}
+
+			w.Header().Set("ETag", `"etag1"`)
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.DownloadChunkSize = 10
+		client.DownloadParallelism = 3
+		client.DownloadRetryDelay = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should download ranges in parallel and retry failed ranges", func() {
+		w := &memoryWriterAt{data: make([]byte, len(content))}
+
+		var progressMutex sync.Mutex
+		var lastDownloaded, lastTotal int64
+
+		item, err := client.ItemsDownload(context.Background(), AddressId("file"), w, func(downloaded int64, total int64) {
+			progressMutex.Lock()
+			defer progressMutex.Unlock()
+			lastDownloaded, lastTotal = downloaded, total
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal("file"))
+		Expect(string(w.data)).To(Equal(content))
+
+		Expect(lastDownloaded).To(Equal(int64(36)))
+		Expect(lastTotal).To(Equal(int64(36)))
+
+		Expect(ranges).To(ContainElements("bytes=0-9", "bytes=10-19", "bytes=20-29", "bytes=23-29", "bytes=30-35"))
+		Expect(ranges).To(HaveLen(7))
+	})
+
+	It("should fail without writing misplaced data if the server ignores Range", func() {
+		ignoreRange = true
+
+		w := &memoryWriterAt{data: make([]byte, len(content))}
+
+		_, err := client.ItemsDownload(context.Background(), AddressId("file"), w, nil)
+		Expect(err).To(Equal(ErrRangeNotHonored))
+
+		for i := 10; i < len(content); i++ {
+			Expect(w.data[i]).To(Equal(byte(0)))
+		}
+	})
+
+	It("should verify written content that can be read back", func() {
+		client.VerifyHashes = true
+
+		w := &memoryWriterAt{data: make([]byte, len(content))}
+
+		_, err := client.ItemsDownload(context.Background(), AddressId("file"), struct {
+			io.WriterAt
+			io.ReaderAt
+		}{w, strings.NewReader(content)}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.ItemsDownload(context.Background(), AddressId("file"), struct {
+			io.WriterAt
+			io.ReaderAt
+		}{w, strings.NewReader(strings.ToUpper(content))}, nil)
+		_, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not retry permanent errors", func() {
+		client.DownloadChunkSize = int64(len(content))
+		client.DownloadRetries = 5
+
+		missing = true
+
+		_, err := client.ItemsDownload(context.Background(), AddressId("file"), &memoryWriterAt{data: make([]byte, len(content))}, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(ranges).To(Equal([]string{"", "bytes=0-35"}))
+	})
+
+	It("should give up after DownloadRetries", func() {
+		client.DownloadRetries = 0
+
+		w := &memoryWriterAt{data: make([]byte, len(content))}
+
+		_, err := client.ItemsDownload(context.Background(), AddressId("file"), w, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
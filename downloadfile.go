@@ -0,0 +1,214 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+const (
+	DownloadPartialSuffix = ".partial"
+	DownloadRecordSuffix  = ".partial.json"
+)
+
+// DownloadRecord is stored next to a partial download and describes which
+// item version the partial file belongs to and how much of it is complete.
+type DownloadRecord struct {
+	ItemId   string `json:"itemId"`
+	ETag     string `json:"eTag"`
+	Size     int64  `json:"size"`
+	Received int64  `json:"received"`
+}
+
+func readDownloadRecord(pth string) (record *DownloadRecord, err error) {
+	data, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	record = &DownloadRecord{}
+
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func writeDownloadRecord(pth string, record *DownloadRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	tmpPth := pth + ".tmp"
+
+	if err := os.WriteFile(tmpPth, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPth, pth)
+}
+
+// ItemsDownloadFile downloads the item at address to localPath. Content is
+// written to localPath+DownloadPartialSuffix and progress is recorded in
+// localPath+DownloadRecordSuffix every DownloadChunkSize bytes. If a previous
+// download of the same item version was interrupted it is resumed from the
+// recorded offset. The partial file is renamed to localPath once complete.
+func (c *OneDrive) ItemsDownloadFile(ctx context.Context, address Address, localPath string, onProgress func(downloaded int64, total int64)) (item *Item, err error) {
+	item, err = c.ItemsGet(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	partialPath := localPath + DownloadPartialSuffix
+	recordPath := localPath + DownloadRecordSuffix
+
+	record := &DownloadRecord{
+		ItemId: item.Id,
+		ETag:   item.ETag,
+		Size:   item.Size,
+	}
+
+	if existing, err := readDownloadRecord(recordPath); err == nil &&
+		existing.ItemId == item.Id && existing.ETag == item.ETag && existing.Size == item.Size {
+
+		if info, err := os.Stat(partialPath); err == nil && info.Size() >= existing.Received {
+			record.Received = existing.Received
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if record.Received == 0 {
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := func() {
+		f.Close()
+		os.Remove(partialPath)
+		os.Remove(recordPath)
+	}
+
+	if err := f.Truncate(record.Received); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if _, err := f.Seek(record.Received, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := writeDownloadRecord(recordPath, record); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if onProgress != nil {
+		onProgress(record.Received, record.Size)
+	}
+
+	reader := c.newItemReader(ctx, item, false)
+	defer reader.Close()
+
+	if _, err := reader.Seek(record.Received, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	chunkSize := c.DownloadChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultDownloadChunkSize
+	}
+
+	for record.Received < record.Size {
+		if err := ctx.Err(); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		n, err := io.CopyN(f, reader, chunkSize)
+
+		record.Received += n
+
+		if n > 0 {
+			if syncErr := f.Sync(); syncErr != nil {
+				f.Close()
+				return nil, syncErr
+			}
+
+			if recordErr := writeDownloadRecord(recordPath, record); recordErr != nil {
+				f.Close()
+				return nil, recordErr
+			}
+
+			if onProgress != nil {
+				onProgress(record.Received, record.Size)
+			}
+		}
+
+		if err == io.EOF && record.Received == record.Size {
+			break
+		}
+
+		if err == ErrItemModified {
+			cleanup()
+			return nil, err
+		}
+
+		// the server ignored Range, the content can only be read from the start
+		if err == ErrRangeNotHonored && n == 0 && record.Received > 0 {
+			record.Received = 0
+
+			if err := f.Truncate(0); err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			if _, err := reader.Seek(0, io.SeekStart); err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	if c.VerifyHashes {
+		if err := verifyFileHash(item, partialPath); err != nil {
+			if _, ok := IsHashMismatchError(err); ok {
+				os.Remove(partialPath)
+				os.Remove(recordPath)
+			}
+			return nil, err
+		}
+	}
+
+	if err := os.Rename(partialPath, localPath); err != nil {
+		return nil, err
+	}
+
+	os.Remove(recordPath)
+
+	return item, nil
+}
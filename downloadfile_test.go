@@ -0,0 +1,153 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemsDownloadFile", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var content string
+	var eTag string
+	var ranges []string
+	var localPath string
+	var file *File
+	var ignoreRange bool
+
+	BeforeEach(func() {
+		content = "0123456789abcdefghijklmnopqrstuvwxyz"
+		eTag = `"etag1"`
+		ranges = nil
+		file = nil
+		ignoreRange = false
+		localPath = filepath.Join(GinkgoT().TempDir(), "file.txt")
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/drives/drive/items/file":
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "file", ETag: eTag, Size: int64(len(content)), File: file})
+			case "/drives/drive/items/file/content":
+				ranges = append(ranges, r.Header.Get("Range"))
+				w.Header().Set("ETag", eTag)
+				if ignoreRange {
+					w.Write([]byte(content))
+					return
+				}
+				http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.DownloadChunkSize = 10
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	interrupted := func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := client.ItemsDownloadFile(ctx, AddressId("file"), localPath, func(downloaded int64, total int64) {
+			if downloaded >= 10 {
+				cancel()
+			}
+		})
+		Expect(err).To(HaveOccurred())
+
+		record, err := readDownloadRecord(localPath + DownloadRecordSuffix)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.Received).To(Equal(int64(10)))
+
+		_, err = os.Stat(localPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	}
+
+	It("should resume an interrupted download", func() {
+		interrupted()
+
+		item, err := client.ItemsDownloadFile(context.Background(), AddressId("file"), localPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal("file"))
+
+		data, err := os.ReadFile(localPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+
+		Expect(ranges).To(Equal([]string{"bytes=0-35", "bytes=10-35"}))
+
+		_, err = os.Stat(localPath + DownloadPartialSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(localPath + DownloadRecordSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should restart if the server ignores Range on resume", func() {
+		interrupted()
+
+		ignoreRange = true
+
+		_, err := client.ItemsDownloadFile(context.Background(), AddressId("file"), localPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(localPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+
+		Expect(ranges).To(Equal([]string{"bytes=0-35", "bytes=10-35", "bytes=0-35"}))
+	})
+
+	It("should restart if the item changed", func() {
+		interrupted()
+
+		eTag = `"etag2"`
+		content = strings.ToUpper(content)
+
+		_, err := client.ItemsDownloadFile(context.Background(), AddressId("file"), localPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(localPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+
+		Expect(ranges).To(Equal([]string{"bytes=0-35", "bytes=0-35"}))
+	})
+
+	It("should verify hashes", func() {
+		client.VerifyHashes = true
+
+		h := NewItemHasher()
+		h.Write([]byte(content))
+		file = &File{Hashes: h.Hashes()}
+
+		_, err := client.ItemsDownloadFile(context.Background(), AddressId("file"), localPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		file = &File{Hashes: Hashes{Sha1Hash: "0000000000000000000000000000000000000000"}}
+
+		os.Remove(localPath)
+
+		_, err = client.ItemsDownloadFile(context.Background(), AddressId("file"), localPath, nil)
+		_, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+
+		_, err = os.Stat(localPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(localPath + DownloadPartialSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
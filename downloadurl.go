@@ -0,0 +1,89 @@
+package onedriveclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/koofr/go-httpclient"
+)
+
+const (
+	// DownloadURLLifetime is a conservative lifetime of pre-authenticated
+	// download URLs, which are documented to be valid for a few minutes only.
+	DownloadURLLifetime = 2 * time.Minute
+)
+
+// DownloadURL is a pre-authenticated content URL. It can be used without an
+// access token until ExpiresAt.
+type DownloadURL struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+func (u *DownloadURL) Expired(now time.Time) bool {
+	return !now.Before(u.ExpiresAt)
+}
+
+// ItemsDownloadURL returns a pre-authenticated download URL for the item at
+// address without downloading its content. The URL is taken from the item
+// metadata or, if missing, from the Location of the content redirect. The
+// expiry is not reported by the API, so ExpiresAt is DownloadURLLifetime after
+// the request was made. Callers should request a new URL rather than cache it
+// past ExpiresAt.
+func (c *OneDrive) ItemsDownloadURL(ctx context.Context, address Address) (downloadURL *DownloadURL, err error) {
+	requestedAt := time.Now()
+
+	item, err := c.ItemsGet(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Folder != nil {
+		return nil, fmt.Errorf("item %s is a folder", item.Id)
+	}
+
+	url := item.GraphDownloadUrl
+	if url == "" {
+		url = item.DownloadUrl
+	}
+
+	if url == "" {
+		requestedAt = time.Now()
+
+		url, err = c.itemsContentLocation(ctx, AddressId(item.Id))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &DownloadURL{
+		URL:       url,
+		ExpiresAt: requestedAt.Add(DownloadURLLifetime),
+	}, nil
+}
+
+func (c *OneDrive) itemsContentLocation(ctx context.Context, address Address) (location string, err error) {
+	req := &httpclient.RequestData{
+		Context:         ctx,
+		Method:          "GET",
+		Path:            address.Subpath("/content").String(c.DriveId),
+		ExpectedStatus:  []int{http.StatusFound},
+		IgnoreRedirects: true,
+	}
+
+	res, err := c.Request(req)
+	if err != nil {
+		return "", err
+	}
+
+	res.Body.Close()
+
+	location = res.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("content redirect location missing")
+	}
+
+	return location, nil
+}
@@ -0,0 +1,68 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemsDownloadURL", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var item *Item
+
+	BeforeEach(func() {
+		item = &Item{Id: "file", File: &File{}}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/drives/drive/items/file":
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(item)
+			case "/drives/drive/items/file/content":
+				w.Header().Set("Location", "https://download.example.com/redirect")
+				w.WriteHeader(http.StatusFound)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return the download url from item metadata", func() {
+		item.GraphDownloadUrl = "https://download.example.com/metadata"
+
+		before := time.Now()
+
+		downloadURL, err := client.ItemsDownloadURL(context.Background(), AddressId("file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloadURL.URL).To(Equal("https://download.example.com/metadata"))
+		Expect(downloadURL.ExpiresAt).To(BeTemporally(">=", before.Add(DownloadURLLifetime)))
+		Expect(downloadURL.Expired(time.Now())).To(BeFalse())
+		Expect(downloadURL.Expired(downloadURL.ExpiresAt)).To(BeTrue())
+	})
+
+	It("should fall back to the content redirect", func() {
+		downloadURL, err := client.ItemsDownloadURL(context.Background(), AddressId("file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloadURL.URL).To(Equal("https://download.example.com/redirect"))
+	})
+
+	It("should fail for folders", func() {
+		item.File = nil
+		item.Folder = &Folder{}
+
+		_, err := client.ItemsDownloadURL(context.Background(), AddressId("file"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -3,8 +3,13 @@ package onedriveclient
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/koofr/go-httpclient"
 )
@@ -16,6 +21,14 @@ const (
 
 var ErrCompletedNoItem = errors.New("Async task completed but no item")
 
+var ErrNotModified = errors.New("Not modified")
+
+var ErrCopyTimeout = errors.New("copy progress too long")
+
+var ErrItemModified = errors.New("Item modified")
+
+var ErrRangeNotHonored = errors.New("Range not honored")
+
 type OneDriveErrorDetails struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -30,6 +43,20 @@ func (e *OneDriveError) Error() string {
 	return e.Err.Message
 }
 
+type AsyncOperationFailedError struct {
+	Status *AsyncOperationStatus
+}
+
+func (e *AsyncOperationFailedError) Error() string {
+	if e.Status.ErrorCode != "" {
+		return "copy failed: " + e.Status.ErrorCode
+	}
+	if e.Status.StatusDescription != "" {
+		return "copy failed: " + e.Status.StatusDescription
+	}
+	return "copy failed"
+}
+
 func IsOneDriveError(err error) (oneDriveErr *OneDriveError, ok bool) {
 	if ode, ok := err.(*OneDriveError); ok {
 		return ode, true
@@ -57,6 +84,64 @@ func IsErrorResync(err error) bool {
 	return false
 }
 
+func IsErrorItemNotFound(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok {
+		return ode.Err.Code == ErrorCodeItemNotFound
+	}
+
+	return false
+}
+
+func IsErrorNameAlreadyExists(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok {
+		return ode.Err.Code == ErrorCodeNameAlreadyExists
+	}
+
+	return false
+}
+
+func IsErrorThrottled(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok && ode.HttpClientError != nil {
+		return ode.HttpClientError.Got == http.StatusTooManyRequests ||
+			ode.HttpClientError.Got == http.StatusServiceUnavailable
+	}
+
+	return false
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of a
+// throttled response.
+func RetryAfter(err error) (delay time.Duration, ok bool) {
+	if !IsErrorThrottled(err) {
+		return 0, false
+	}
+
+	ode, _ := IsOneDriveError(err)
+
+	seconds, parseErr := strconv.Atoi(ode.HttpClientError.Headers.Get("Retry-After"))
+	if parseErr != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+func IsErrorPreconditionFailed(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok {
+		return ode.HttpClientError != nil && ode.HttpClientError.Got == http.StatusPreconditionFailed
+	}
+
+	return false
+}
+
+func IsErrorRangeNotSatisfiable(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok {
+		return ode.HttpClientError != nil && ode.HttpClientError.Got == http.StatusRequestedRangeNotSatisfiable
+	}
+
+	return false
+}
+
 func HandleError(err error) error {
 	if ise, ok := httpclient.IsInvalidStatusError(err); ok {
 		oneDriveErr := &OneDriveError{}
@@ -82,3 +167,29 @@ func HandleError(err error) error {
 		return err
 	}
 }
+
+// isRetryableError reports whether a failed upload or download request may
+// succeed if repeated: transport errors, timeouts, throttling, server errors
+// and rejected upload ranges, which are resynchronized.
+func isRetryableError(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok {
+		if ode.HttpClientError == nil {
+			return false
+		}
+
+		switch got := ode.HttpClientError.Got; {
+		case got == http.StatusRequestTimeout,
+			got == http.StatusTooManyRequests,
+			got == http.StatusRequestedRangeNotSatisfiable,
+			got >= 500:
+			return true
+		}
+
+		return false
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+
+	return errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
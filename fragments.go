@@ -0,0 +1,108 @@
+package onedriveclient
+
+import (
+	"time"
+)
+
+const (
+	// FragmentSizeUnit is the granularity of upload session fragments. Every
+	// fragment except the last must be a multiple of it.
+	FragmentSizeUnit = 320 * 1024
+	// FragmentSizeLimit is the largest fragment accepted by the API.
+	FragmentSizeLimit = 60 * 1024 * 1024
+
+	DefaultAdaptiveFragmentInitialSize = 16 * FragmentSizeUnit
+	DefaultAdaptiveFragmentDuration    = 10 * time.Second
+)
+
+// AlignFragmentSize rounds size down to a multiple of FragmentSizeUnit and
+// clamps it to [FragmentSizeUnit, FragmentSizeLimit].
+func AlignFragmentSize(size int64) int64 {
+	size -= size % FragmentSizeUnit
+
+	if size < FragmentSizeUnit {
+		return FragmentSizeUnit
+	}
+
+	if size > FragmentSizeLimit {
+		return FragmentSizeLimit
+	}
+
+	return size
+}
+
+// fragmentSizer chooses upload fragment sizes. If adaptive, the size is
+// adjusted after every fragment so that one fragment takes about target to
+// upload, and halved after a failure. The size never exceeds max.
+type fragmentSizer struct {
+	size     int64
+	max      int64
+	adaptive bool
+	target   time.Duration
+}
+
+func (c *OneDrive) newFragmentSizer() *fragmentSizer {
+	s := &fragmentSizer{
+		max:      AlignFragmentSize(c.MaxFragmentSize),
+		adaptive: c.AdaptiveFragmentSize,
+		target:   c.AdaptiveFragmentDuration,
+	}
+
+	s.size = s.max
+
+	if s.adaptive {
+		if s.target <= 0 {
+			s.target = DefaultAdaptiveFragmentDuration
+		}
+
+		if s.max > DefaultAdaptiveFragmentInitialSize {
+			s.size = DefaultAdaptiveFragmentInitialSize
+		}
+	}
+
+	return s
+}
+
+func (s *fragmentSizer) next() int64 {
+	return s.size
+}
+
+func (s *fragmentSizer) resize(size int64) {
+	size = AlignFragmentSize(size)
+
+	if size > s.max {
+		size = s.max
+	}
+
+	s.size = size
+}
+
+func (s *fragmentSizer) uploaded(n int64, duration time.Duration) {
+	if !s.adaptive || n <= 0 {
+		return
+	}
+
+	if duration <= 0 {
+		s.resize(s.size * 2)
+		return
+	}
+
+	bytesPerSecond := float64(n) / duration.Seconds()
+
+	size := int64(bytesPerSecond * s.target.Seconds())
+
+	// grow gradually to avoid one fast fragment causing a huge next one
+	if size > s.size*2 {
+		size = s.size * 2
+	}
+
+	s.resize(size)
+}
+
+func (s *fragmentSizer) failed() {
+	if !s.adaptive {
+		return
+	}
+
+	s.resize(s.size / 2)
+}
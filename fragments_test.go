@@ -0,0 +1,142 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fragments", func() {
+	const u = FragmentSizeUnit
+
+	Describe("AlignFragmentSize", func() {
+		It("should align to FragmentSizeUnit within limits", func() {
+			Expect(AlignFragmentSize(0)).To(Equal(int64(u)))
+			Expect(AlignFragmentSize(3)).To(Equal(int64(u)))
+			Expect(AlignFragmentSize(u)).To(Equal(int64(u)))
+			Expect(AlignFragmentSize(2*u - 1)).To(Equal(int64(u)))
+			Expect(AlignFragmentSize(2 * u)).To(Equal(int64(2 * u)))
+			Expect(AlignFragmentSize(FragmentSizeLimit)).To(Equal(int64(FragmentSizeLimit)))
+			Expect(AlignFragmentSize(FragmentSizeLimit + u)).To(Equal(int64(FragmentSizeLimit)))
+			Expect(FragmentSizeLimit % u).To(Equal(0))
+		})
+	})
+
+	Describe("fragmentSizer", func() {
+		It("should use the aligned MaxFragmentSize", func() {
+			client := NewOneDrive(&OneDriveAuth{})
+			client.MaxFragmentSize = 5*u + 100
+
+			sizer := client.newFragmentSizer()
+			Expect(sizer.next()).To(Equal(int64(5 * u)))
+
+			sizer.uploaded(5*u, time.Hour)
+			sizer.failed()
+			Expect(sizer.next()).To(Equal(int64(5 * u)))
+		})
+
+		It("should adapt to throughput", func() {
+			client := NewOneDrive(&OneDriveAuth{})
+			client.AdaptiveFragmentSize = true
+			client.AdaptiveFragmentDuration = 10 * time.Second
+
+			sizer := client.newFragmentSizer()
+			Expect(sizer.next()).To(Equal(int64(DefaultAdaptiveFragmentInitialSize)))
+
+			// 1 unit per second
+			sizer.uploaded(16*u, 16*time.Second)
+			Expect(sizer.next()).To(Equal(int64(10 * u)))
+
+			// growth is limited to doubling
+			sizer.uploaded(10*u, time.Second)
+			Expect(sizer.next()).To(Equal(int64(20 * u)))
+
+			sizer.failed()
+			Expect(sizer.next()).To(Equal(int64(10 * u)))
+
+			// never below one unit or above MaxFragmentSize
+			sizer.uploaded(u, time.Hour)
+			Expect(sizer.next()).To(Equal(int64(u)))
+
+			for i := 0; i < 20; i++ {
+				sizer.uploaded(sizer.next(), time.Millisecond)
+			}
+			Expect(sizer.next()).To(Equal(int64(FragmentSizeLimit)))
+		})
+	})
+
+	Describe("upload sessions", func() {
+		var server *httptest.Server
+		var upload *standInUploadServer
+		var client *OneDrive
+
+		BeforeEach(func() {
+			upload = &standInUploadServer{}
+			server = httptest.NewServer(upload)
+
+			client = newStandInOneDrive(server.URL, "drive")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		ranges := func() []string {
+			puts := []string{}
+			for _, r := range upload.requests {
+				if strings.HasPrefix(r, "PUT ") {
+					puts = append(puts, r)
+				}
+			}
+			return puts
+		}
+
+		It("should send aligned fragments for unaligned MaxFragmentSize", func() {
+			client.MaxFragmentSize = 2*u + 1000
+
+			content, _ := standInUploadContent(5*u + 1)
+
+			_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(upload.data)).To(Equal(content))
+
+			Expect(ranges()).To(Equal([]string{
+				fmt.Sprintf("PUT %d-%d", 0, 2*u-1),
+				fmt.Sprintf("PUT %d-%d", 2*u, 4*u-1),
+				fmt.Sprintf("PUT %d-%d", 4*u, 5*u),
+			}))
+		})
+
+		It("should send a single fragment for content of exactly MaxFragmentSize", func() {
+			client.MaxFragmentSize = 2 * u
+
+			content, _ := standInUploadContent(2 * u)
+
+			_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, bytes.NewBufferString(content), int64(len(content)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(upload.data)).To(Equal(content))
+
+			Expect(ranges()).To(Equal([]string{
+				fmt.Sprintf("PUT %d-%d", 0, 2*u-1),
+			}))
+		})
+
+		It("should use at least one unit for tiny MaxFragmentSize", func() {
+			client.MaxFragmentSize = 3
+
+			content, puts := standInUploadContent(u + 1)
+
+			_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, bytes.NewBufferString(content), int64(len(content)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(upload.data)).To(Equal(content))
+
+			Expect(ranges()).To(Equal(puts))
+		})
+	})
+})
@@ -0,0 +1,166 @@
+package onedriveclient
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	HashAlgorithmQuickXor = "quickXorHash"
+	HashAlgorithmSha256   = "sha256Hash"
+	HashAlgorithmSha1     = "sha1Hash"
+)
+
+type HashMismatchError struct {
+	Item      *Item
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch for item %s: expected %s, got %s", e.Algorithm, e.Item.Id, e.Expected, e.Actual)
+}
+
+func IsHashMismatchError(err error) (hashErr *HashMismatchError, ok bool) {
+	if hme, ok := err.(*HashMismatchError); ok {
+		return hme, true
+	} else {
+		return nil, false
+	}
+}
+
+// ItemHasher computes all content hashes OneDrive may report for an item.
+type ItemHasher struct {
+	quickXor hash.Hash
+	sha256   hash.Hash
+	sha1     hash.Hash
+	w        io.Writer
+}
+
+func NewItemHasher() *ItemHasher {
+	h := &ItemHasher{
+		quickXor: NewQuickXorHash(),
+		sha256:   sha256.New(),
+		sha1:     sha1.New(),
+	}
+
+	h.w = io.MultiWriter(h.quickXor, h.sha256, h.sha1)
+
+	return h
+}
+
+func (h *ItemHasher) Write(p []byte) (n int, err error) {
+	return h.w.Write(p)
+}
+
+func (h *ItemHasher) Hashes() Hashes {
+	return Hashes{
+		QuickXorHash: base64.StdEncoding.EncodeToString(h.quickXor.Sum(nil)),
+		Sha256Hash:   strings.ToUpper(hex.EncodeToString(h.sha256.Sum(nil))),
+		Sha1Hash:     strings.ToUpper(hex.EncodeToString(h.sha1.Sum(nil))),
+	}
+}
+
+// Verify compares the written content with the strongest hash reported for
+// item. Items without hashes are not verified.
+func (h *ItemHasher) Verify(item *Item) error {
+	if item == nil || item.File == nil {
+		return nil
+	}
+
+	expected := item.File.Hashes
+	actual := h.Hashes()
+
+	mismatch := func(algorithm string, expected string, actual string) error {
+		return &HashMismatchError{
+			Item:      item,
+			Algorithm: algorithm,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+
+	switch {
+	case expected.QuickXorHash != "":
+		if expected.QuickXorHash != actual.QuickXorHash {
+			return mismatch(HashAlgorithmQuickXor, expected.QuickXorHash, actual.QuickXorHash)
+		}
+	case expected.Sha256Hash != "":
+		if !strings.EqualFold(expected.Sha256Hash, actual.Sha256Hash) {
+			return mismatch(HashAlgorithmSha256, expected.Sha256Hash, actual.Sha256Hash)
+		}
+	case expected.Sha1Hash != "":
+		if !strings.EqualFold(expected.Sha1Hash, actual.Sha1Hash) {
+			return mismatch(HashAlgorithmSha1, expected.Sha1Hash, actual.Sha1Hash)
+		}
+	}
+
+	return nil
+}
+
+// hashVerifyingReader verifies the content read from r when it reaches EOF.
+type hashVerifyingReader struct {
+	r      io.ReadCloser
+	item   *Item
+	hasher *ItemHasher
+	err    error
+}
+
+func newHashVerifyingReader(r io.ReadCloser, item *Item) *hashVerifyingReader {
+	return &hashVerifyingReader{
+		r:      r,
+		item:   item,
+		hasher: NewItemHasher(),
+	}
+}
+
+func (r *hashVerifyingReader) Read(p []byte) (n int, err error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err = r.r.Read(p)
+
+	r.hasher.Write(p[:n])
+
+	if err == io.EOF {
+		if verifyErr := r.hasher.Verify(r.item); verifyErr != nil {
+			r.err = verifyErr
+			return n, verifyErr
+		}
+	}
+
+	return n, err
+}
+
+func (r *hashVerifyingReader) Close() error {
+	return r.r.Close()
+}
+
+func VerifyItemHash(item *Item, content io.Reader) error {
+	h := NewItemHasher()
+
+	if _, err := io.Copy(h, content); err != nil {
+		return err
+	}
+
+	return h.Verify(item)
+}
+
+func verifyFileHash(item *Item, pth string) error {
+	f, err := os.Open(pth)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return VerifyItemHash(item, f)
+}
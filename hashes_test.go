@@ -0,0 +1,87 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/rand"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuickXorHash", func() {
+	sum := func(data []byte) string {
+		h := NewQuickXorHash()
+		h.Write(data)
+		return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+
+	It("should match test vectors", func() {
+		vectors := map[string]string{
+			"":     "AAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+			"Sg==": "SgAAAAAAAAAAAAAAAQAAAAAAAAA=",
+			"tbQ=": "taAFAAAAAAAAAAAAAgAAAAAAAAA=",
+		}
+
+		for input, expected := range vectors {
+			data, err := base64.StdEncoding.DecodeString(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sum(data)).To(Equal(expected), input)
+		}
+	})
+
+	It("should not depend on how the input is split", func() {
+		data := make([]byte, 5000)
+		rand.New(rand.NewSource(1)).Read(data)
+
+		expected := sum(data)
+
+		for _, chunkSize := range []int{1, 7, 64, 159, 160, 161, 1000} {
+			h := NewQuickXorHash()
+			for i := 0; i < len(data); i += chunkSize {
+				end := i + chunkSize
+				if end > len(data) {
+					end = len(data)
+				}
+				h.Write(data[i:end])
+			}
+
+			Expect(base64.StdEncoding.EncodeToString(h.Sum(nil))).To(Equal(expected), "chunk size %d", chunkSize)
+		}
+	})
+
+	It("should reset", func() {
+		h := NewQuickXorHash()
+		h.Write([]byte("data"))
+		h.Reset()
+
+		Expect(h.Sum(nil)).To(Equal(make([]byte, QuickXorHashSize)))
+		Expect(h.Size()).To(Equal(QuickXorHashSize))
+	})
+})
+
+var _ = Describe("VerifyItemHash", func() {
+	item := func(hashes Hashes) *Item {
+		return &Item{Id: "file", File: &File{Hashes: hashes}}
+	}
+
+	It("should verify the strongest available hash", func() {
+		h := NewItemHasher()
+		h.Write([]byte("hello"))
+		hashes := h.Hashes()
+
+		Expect(hashes.Sha1Hash).To(Equal("AAF4C61DDCC5E8A2DABEDE0F3B482CD9AEA9434D"))
+
+		Expect(VerifyItemHash(item(hashes), strings.NewReader("hello"))).To(Succeed())
+		Expect(VerifyItemHash(item(Hashes{Sha256Hash: strings.ToLower(hashes.Sha256Hash)}), strings.NewReader("hello"))).To(Succeed())
+		Expect(VerifyItemHash(item(Hashes{Sha1Hash: hashes.Sha1Hash}), strings.NewReader("hello"))).To(Succeed())
+		Expect(VerifyItemHash(item(Hashes{}), strings.NewReader("hello"))).To(Succeed())
+
+		err := VerifyItemHash(item(hashes), bytes.NewReader([]byte("hellO")))
+		hashErr, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+		Expect(hashErr.Algorithm).To(Equal(HashAlgorithmQuickXor))
+		Expect(hashErr.Expected).To(Equal(hashes.QuickXorHash))
+	})
+})
@@ -0,0 +1,259 @@
+package onedriveclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ItemReader reads item content using range requests. Sequential reads reuse
+// the open response body, seeks reopen it lazily on the next read. All
+// requests are pinned to the eTag the reader was opened with, so an item
+// overwritten while reading fails with ErrItemModified. If VerifyHashes is
+// set, content read sequentially from the start is verified when Read reaches
+// the end.
+type ItemReader struct {
+	client  *OneDrive
+	ctx     context.Context
+	item    *Item
+	address Address
+
+	mutex      sync.Mutex
+	offset     int64
+	body       io.ReadCloser
+	bodyOffset int64
+
+	hasher    *ItemHasher
+	hashed    int64
+	verifyErr error
+}
+
+var _ io.ReadSeekCloser = (*ItemReader)(nil)
+var _ io.ReaderAt = (*ItemReader)(nil)
+
+func (c *OneDrive) NewItemReader(ctx context.Context, address Address) (reader *ItemReader, err error) {
+	item, err := c.ItemsGet(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.NewItemReaderFromItem(ctx, item), nil
+}
+
+func (c *OneDrive) NewItemReaderFromItem(ctx context.Context, item *Item) *ItemReader {
+	return c.newItemReader(ctx, item, c.VerifyHashes)
+}
+
+// newItemReader is used by downloads that verify the written content
+// themselves with verify unset.
+func (c *OneDrive) newItemReader(ctx context.Context, item *Item, verify bool) *ItemReader {
+	r := &ItemReader{
+		client:  c,
+		ctx:     ctx,
+		item:    item,
+		address: AddressId(item.Id),
+	}
+
+	if verify {
+		r.hasher = NewItemHasher()
+	}
+
+	return r
+}
+
+func (r *ItemReader) Item() *Item {
+	return r.item
+}
+
+func (r *ItemReader) Size() int64 {
+	return r.item.Size
+}
+
+func normalizeETag(eTag string) string {
+	return strings.Trim(strings.TrimPrefix(eTag, "W/"), `"`)
+}
+
+// open requests content from start to end. If-Match may not survive the
+// redirect to the download host, so the response ETag is checked as well.
+// A server that ignores Range is only accepted when reading from the start.
+func (r *ItemReader) open(start int64, end int64) (body io.ReadCloser, err error) {
+	var cond *Precondition
+	if r.item.ETag != "" {
+		cond = IfMatch(r.item.ETag)
+	}
+
+	body, info, err := r.client.ItemsContentRange(r.ctx, r.address, RangeSpan(start, end), cond)
+	if IsErrorPreconditionFailed(err) {
+		return nil, ErrItemModified
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.ETag != "" && r.item.ETag != "" && normalizeETag(info.ETag) != normalizeETag(r.item.ETag) {
+		body.Close()
+		return nil, ErrItemModified
+	}
+
+	if (info.Partial && info.Start != start) || (!info.Partial && start != 0) {
+		body.Close()
+		return nil, ErrRangeNotHonored
+	}
+
+	return body, nil
+}
+
+// verify checks the hashes once all content was read in order from the start.
+// Content read otherwise is not verified.
+func (r *ItemReader) verify() error {
+	if r.verifyErr != nil || r.hasher == nil || r.hashed != r.item.Size {
+		return r.verifyErr
+	}
+
+	r.verifyErr = r.hasher.Verify(r.item)
+	r.hasher = nil
+
+	return r.verifyErr
+}
+
+func (r *ItemReader) closeBody() {
+	if r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+}
+
+func (r *ItemReader) Read(p []byte) (n int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.offset >= r.item.Size {
+		if err := r.verify(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if r.body != nil && r.bodyOffset != r.offset {
+		r.closeBody()
+	}
+
+	if r.body == nil {
+		body, err := r.open(r.offset, r.item.Size-1)
+		if err != nil {
+			return 0, err
+		}
+
+		r.body = body
+		r.bodyOffset = r.offset
+	}
+
+	if left := r.item.Size - r.offset; int64(len(p)) > left {
+		p = p[:left]
+	}
+
+	n, err = r.body.Read(p)
+
+	if r.hasher != nil && r.hashed == r.offset {
+		r.hasher.Write(p[:n])
+		r.hashed += int64(n)
+	}
+
+	r.offset += int64(n)
+	r.bodyOffset += int64(n)
+
+	if err == io.EOF {
+		r.closeBody()
+
+		if r.offset < r.item.Size {
+			if n == 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return n, nil
+		}
+	}
+
+	if r.offset >= r.item.Size {
+		if verifyErr := r.verify(); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+
+	return n, err
+}
+
+func (r *ItemReader) Seek(offset int64, whence int) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.item.Size
+	default:
+		return 0, errors.New("ItemReader.Seek: invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("ItemReader.Seek: negative position")
+	}
+
+	r.offset = offset
+
+	return offset, nil
+}
+
+// ReadAt issues a separate range request and does not affect the offset used
+// by Read and Seek. It is safe to call concurrently.
+func (r *ItemReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("ItemReader.ReadAt: negative offset")
+	}
+
+	if off >= r.item.Size {
+		return 0, io.EOF
+	}
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	end := off + int64(len(p))
+	if end > r.item.Size {
+		end = r.item.Size
+	}
+
+	body, err := r.open(off, end-1)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err = io.ReadFull(body, p[:end-off])
+	if err != nil {
+		return n, err
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func (r *ItemReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.closeBody()
+
+	return nil
+}
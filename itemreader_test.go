@@ -0,0 +1,218 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemReader", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var content string
+	var eTag string
+	var ranges []string
+	var ignoreRange bool
+	var ignoreIfMatch bool
+	var hashes Hashes
+
+	BeforeEach(func() {
+		content = "0123456789abcdefghij"
+		eTag = `"etag1"`
+		ranges = nil
+		ignoreRange = false
+		ignoreIfMatch = false
+
+		h := NewItemHasher()
+		h.Write([]byte(content))
+		hashes = h.Hashes()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/drives/drive/items/file" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "file", ETag: eTag, Size: int64(len(content)), File: &File{Hashes: hashes}})
+				return
+			}
+
+			if r.URL.Path != "/drives/drive/items/file/content" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			ranges = append(ranges, r.Header.Get("Range"))
+
+			w.Header().Set("ETag", eTag)
+
+			if ignoreRange {
+				w.Write([]byte(content))
+				return
+			}
+
+			if ignoreIfMatch {
+				r.Header.Del("If-Match")
+			}
+
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newReader := func() *ItemReader {
+		return client.NewItemReaderFromItem(context.Background(), &Item{
+			Id:   "file",
+			ETag: `"etag1"`,
+			Size: int64(len(content)),
+			File: &File{Hashes: hashes},
+		})
+	}
+
+	It("should read sequentially with a single request", func() {
+		reader := newReader()
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+		Expect(ranges).To(Equal([]string{"bytes=0-19"}))
+	})
+
+	It("should reopen the stream after seeking", func() {
+		reader := newReader()
+		defer reader.Close()
+
+		buf := make([]byte, 3)
+
+		_, err := io.ReadFull(reader, buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).To(Equal("012"))
+
+		_, err = io.ReadFull(reader, buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).To(Equal("345"))
+
+		pos, err := reader.Seek(-4, io.SeekEnd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pos).To(Equal(int64(16)))
+
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("ghij"))
+
+		Expect(ranges).To(Equal([]string{"bytes=0-19", "bytes=16-19"}))
+	})
+
+	It("should read at offsets", func() {
+		reader := newReader()
+
+		buf := make([]byte, 4)
+
+		n, err := reader.ReadAt(buf, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(4))
+		Expect(string(buf)).To(Equal("abcd"))
+
+		n, err = reader.ReadAt(buf, 18)
+		Expect(err).To(Equal(io.EOF))
+		Expect(n).To(Equal(2))
+		Expect(string(buf[:n])).To(Equal("ij"))
+
+		Expect(ranges).To(Equal([]string{"bytes=10-13", "bytes=18-19"}))
+	})
+
+	It("should fail if the item was modified", func() {
+		reader := newReader()
+
+		eTag = `"etag2"`
+
+		_, err := reader.Read(make([]byte, 1))
+		Expect(err).To(Equal(ErrItemModified))
+
+		_, err = reader.ReadAt(make([]byte, 1), 0)
+		Expect(err).To(Equal(ErrItemModified))
+	})
+
+	It("should fail if the response eTag differs", func() {
+		reader := newReader()
+
+		eTag = `"etag2"`
+		ignoreIfMatch = true
+
+		_, err := reader.ReadAt(make([]byte, 1), 3)
+		Expect(err).To(Equal(ErrItemModified))
+	})
+
+	It("should fail if the server ignores Range", func() {
+		ignoreRange = true
+
+		reader := newReader()
+
+		_, err := reader.ReadAt(make([]byte, 3), 5)
+		Expect(err).To(Equal(ErrRangeNotHonored))
+
+		reader.Seek(5, io.SeekStart)
+		_, err = reader.Read(make([]byte, 3))
+		Expect(err).To(Equal(ErrRangeNotHonored))
+
+		buf := make([]byte, 3)
+		_, err = reader.ReadAt(buf, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).To(Equal("012"))
+	})
+
+	It("should be usable as io.ReadSeeker", func() {
+		var buf bytes.Buffer
+
+		reader := newReader()
+		reader.Seek(5, io.SeekStart)
+		reader.Seek(2, io.SeekCurrent)
+
+		_, err := io.Copy(&buf, reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(content[7:]))
+	})
+
+	It("should verify hashes of sequential reads", func() {
+		client.VerifyHashes = true
+
+		data, err := io.ReadAll(newReader())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+
+		hashes = Hashes{QuickXorHash: "invalid"}
+
+		_, err = io.ReadAll(newReader())
+		_, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should verify hashes of whole content", func() {
+		client.VerifyHashes = true
+
+		reader, _, err := client.ItemsContent(context.Background(), AddressId("file"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(content))
+
+		hashes = Hashes{QuickXorHash: "invalid"}
+
+		reader, _, err = client.ItemsContent(context.Background(), AddressId("file"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = io.ReadAll(reader)
+		_, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -0,0 +1,85 @@
+package mirror
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type fileStoreData struct {
+	Cursor  string   `json:"cursor"`
+	Entries []*Entry `json:"entries"`
+}
+
+// FileStore keeps entries in memory and writes them to a JSON file on Commit.
+type FileStore struct {
+	*MemoryStore
+
+	path string
+}
+
+func NewFileStore(path string) (s *FileStore, err error) {
+	s = &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
+	}
+
+	buf, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := &fileStoreData{}
+
+	if err = json.Unmarshal(buf, data); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range data.Entries {
+		if err = s.Put(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.SetCursor(data.Cursor); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Commit() error {
+	cursor, err := s.Cursor()
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(&fileStoreData{
+		Cursor:  cursor,
+		Entries: s.all(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(buf); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
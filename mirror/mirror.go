@@ -0,0 +1,222 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/koofr/go-onedriveclient"
+)
+
+var ErrNotFound = errors.New("Entry not found")
+
+// Mirror maintains a local model of a remote tree by applying ItemsDelta
+// changes to a Store. Lookups are answered from the store only. The item at
+// address is stored as the root entry, so paths are relative to it.
+type Mirror struct {
+	client  *onedriveclient.OneDrive
+	address onedriveclient.Address
+	store   Store
+	rootId  string
+}
+
+func New(client *onedriveclient.OneDrive, address onedriveclient.Address, store Store) *Mirror {
+	return &Mirror{
+		client:  client,
+		address: address,
+		store:   store,
+	}
+}
+
+// Sync applies all changes since the stored cursor. If the cursor is no
+// longer valid the store is reset and rebuilt from a full enumeration.
+func (m *Mirror) Sync(ctx context.Context) error {
+	cursor, err := m.store.Cursor()
+	if err != nil {
+		return err
+	}
+
+	err = m.sync(ctx, cursor)
+
+	if err != nil && cursor != "" && onedriveclient.IsErrorResync(err) {
+		if err = m.store.Reset(); err != nil {
+			return err
+		}
+
+		err = m.sync(ctx, "")
+	}
+
+	return err
+}
+
+// ResolveRoot looks up the id of the item at address. It is called by Sync
+// and only needs to be called before ApplyItems if the mirror is not rooted
+// at the drive root.
+func (m *Mirror) ResolveRoot(ctx context.Context) error {
+	if m.rootId != "" || m.address == onedriveclient.AddressRoot {
+		return nil
+	}
+
+	item, err := m.client.ItemsGet(ctx, m.address)
+	if err != nil {
+		return err
+	}
+
+	m.rootId = item.Id
+
+	return nil
+}
+
+func (m *Mirror) isRoot(item *onedriveclient.Item) bool {
+	if m.rootId != "" {
+		return item.Id == m.rootId
+	}
+
+	return m.address == onedriveclient.AddressRoot &&
+		(item.Root != nil || (item.ParentReference == nil && item.Name == "root"))
+}
+
+func (m *Mirror) sync(ctx context.Context, cursor string) error {
+	if err := m.ResolveRoot(ctx); err != nil {
+		return err
+	}
+
+	session := m.client.NewDeltaSession(m.address, cursor)
+
+	cursor, err := session.Sync(ctx, m.ApplyItems)
+	if err != nil {
+		return err
+	}
+
+	if err = m.store.SetCursor(cursor); err != nil {
+		return err
+	}
+
+	return m.store.Commit()
+}
+
+func (m *Mirror) ApplyPage(page *onedriveclient.DeltaCollectionPage) error {
+	return m.ApplyItems(page.Value)
+}
+
+func (m *Mirror) ApplyItems(items []*onedriveclient.Item) error {
+	for _, item := range items {
+		if item.Deleted != nil {
+			if err := m.deleteTree(item.Id); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := m.store.Put(m.entryFromItem(item)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Mirror) deleteTree(id string) error {
+	children, err := m.store.Children(id)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err = m.deleteTree(child.Id); err != nil {
+			return err
+		}
+	}
+
+	return m.store.Delete(id)
+}
+
+func (m *Mirror) entryFromItem(item *onedriveclient.Item) *Entry {
+	entry := &Entry{
+		Id:       item.Id,
+		Name:     item.Name,
+		IsRoot:   m.isRoot(item),
+		IsFolder: item.Folder != nil,
+		Size:     item.Size,
+		ETag:     item.ETag,
+		CTag:     item.CTag,
+		Modified: item.LastModifiedDateTime,
+	}
+
+	if item.ParentReference != nil {
+		entry.ParentId = item.ParentReference.Id
+	}
+
+	return entry
+}
+
+func (m *Mirror) GetById(id string) (entry *Entry, err error) {
+	entry, ok, err := m.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return entry, nil
+}
+
+func (m *Mirror) GetByPath(pth string) (entry *Entry, err error) {
+	entry, ok, err := m.store.Root()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	pth = onedriveclient.NormalizePath(pth)
+
+	if pth == "/" {
+		return entry, nil
+	}
+
+	for _, name := range strings.Split(pth[1:], "/") {
+		entry, ok, err = m.store.Child(entry.Id, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrNotFound
+		}
+	}
+
+	return entry, nil
+}
+
+func (m *Mirror) Path(id string) (pth string, err error) {
+	names := []string{}
+	visited := map[string]bool{}
+
+	for {
+		if visited[id] {
+			return "", ErrNotFound
+		}
+		visited[id] = true
+
+		entry, err := m.GetById(id)
+		if err != nil {
+			return "", err
+		}
+
+		if entry.IsRoot {
+			break
+		}
+
+		names = append([]string{entry.Name}, names...)
+		id = entry.ParentId
+	}
+
+	return "/" + strings.Join(names, "/"), nil
+}
+
+func (m *Mirror) Children(id string) (entries []*Entry, err error) {
+	return m.store.Children(id)
+}
@@ -0,0 +1,207 @@
+package mirror_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/koofr/go-onedriveclient"
+	"github.com/koofr/go-onedriveclient/mirror"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mirror", func() {
+	item := func(id string, name string, parentId string, folder bool) *onedriveclient.Item {
+		item := &onedriveclient.Item{
+			Id:              id,
+			Name:            name,
+			ParentReference: &onedriveclient.ItemReference{Id: parentId},
+		}
+		if folder {
+			item.Folder = &onedriveclient.Folder{}
+		}
+		return item
+	}
+
+	initial := []*onedriveclient.Item{
+		{Id: "root", Name: "root", Root: &onedriveclient.Root{}, Folder: &onedriveclient.Folder{}},
+		item("a", "Dir", "root", true),
+		item("b", "file.txt", "a", false),
+	}
+
+	Describe("ApplyItems", func() {
+		var m *mirror.Mirror
+
+		BeforeEach(func() {
+			m = mirror.New(nil, onedriveclient.AddressRoot, mirror.NewMemoryStore())
+
+			Expect(m.ApplyItems(initial)).To(Succeed())
+		})
+
+		It("should look up entries by path and id", func() {
+			entry, err := m.GetByPath("/dir/FILE.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("b"))
+
+			pth, err := m.Path("b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pth).To(Equal("/Dir/file.txt"))
+
+			entry, err = m.GetByPath("/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.IsRoot).To(BeTrue())
+		})
+
+		It("should apply moves", func() {
+			Expect(m.ApplyItems([]*onedriveclient.Item{item("b", "moved.txt", "root", false)})).To(Succeed())
+
+			_, err := m.GetByPath("/Dir/file.txt")
+			Expect(err).To(Equal(mirror.ErrNotFound))
+
+			entry, err := m.GetByPath("/moved.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("b"))
+		})
+
+		It("should delete subtrees", func() {
+			Expect(m.ApplyItems([]*onedriveclient.Item{{Id: "a", Deleted: &onedriveclient.Deleted{}}})).To(Succeed())
+
+			_, err := m.GetById("b")
+			Expect(err).To(Equal(mirror.ErrNotFound))
+
+			children, err := m.Children("root")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(children).To(BeEmpty())
+		})
+	})
+
+	Describe("FileStore", func() {
+		It("should persist entries and cursor on commit", func() {
+			dir, err := os.MkdirTemp("", "mirror")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			pth := filepath.Join(dir, "mirror.json")
+
+			store, err := mirror.NewFileStore(pth)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mirror.New(nil, onedriveclient.AddressRoot, store).ApplyItems(initial)).To(Succeed())
+			Expect(store.SetCursor("cursor")).To(Succeed())
+			Expect(store.Commit()).To(Succeed())
+
+			loaded, err := mirror.NewFileStore(pth)
+			Expect(err).NotTo(HaveOccurred())
+
+			cursor, err := loaded.Cursor()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cursor).To(Equal("cursor"))
+
+			entry, err := mirror.New(nil, onedriveclient.AddressRoot, loaded).GetByPath("/Dir/file.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("b"))
+		})
+	})
+
+	Describe("Sync", func() {
+		It("should rebuild when a resync is required", func() {
+			fullSyncs := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.URL.Query().Get("token") == "stale" {
+					w.WriteHeader(http.StatusGone)
+					w.Write([]byte(`{"error":{"code":"resyncRequired","message":"Resync required"}}`))
+					return
+				}
+
+				fullSyncs++
+
+				items := initial
+				if fullSyncs == 1 {
+					items = append([]*onedriveclient.Item{item("gone", "gone.txt", "root", false)}, initial...)
+				}
+
+				json.NewEncoder(w).Encode(&onedriveclient.DeltaCollectionPage{
+					Value:     items,
+					DeltaLink: "http://" + r.Host + "/delta?token=stale",
+				})
+			}))
+			defer server.Close()
+
+			client := onedriveclient.NewOneDriveGraph(&onedriveclient.OneDriveAuth{
+				AccessToken: "token",
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}, "drive")
+			client.ApiClient.BaseURL, _ = url.Parse(server.URL)
+
+			store := mirror.NewMemoryStore()
+
+			m := mirror.New(client, onedriveclient.AddressRoot, store)
+
+			Expect(m.Sync(context.Background())).To(Succeed())
+
+			_, err := m.GetById("gone")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Sync(context.Background())).To(Succeed())
+			Expect(fullSyncs).To(Equal(2))
+
+			_, err = m.GetById("gone")
+			Expect(err).To(Equal(mirror.ErrNotFound))
+
+			entry, err := m.GetByPath("/Dir/file.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("b"))
+		})
+
+		It("should be rooted at a folder address", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.URL.Path {
+				case "/drives/drive/root:/Dir:":
+					json.NewEncoder(w).Encode(item("a", "Dir", "root", true))
+				case "/drives/drive/root:/Dir:/delta":
+					json.NewEncoder(w).Encode(&onedriveclient.DeltaCollectionPage{
+						Value:     initial[1:],
+						DeltaLink: "http://" + r.Host + "/delta?token=t1",
+					})
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := onedriveclient.NewOneDriveGraph(&onedriveclient.OneDriveAuth{
+				AccessToken: "token",
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}, "drive")
+			client.ApiClient.BaseURL, _ = url.Parse(server.URL)
+
+			m := mirror.New(client, onedriveclient.AddressPath("/Dir"), mirror.NewMemoryStore())
+
+			Expect(m.Sync(context.Background())).To(Succeed())
+
+			entry, err := m.GetByPath("/file.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("b"))
+
+			entry, err = m.GetByPath("/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Id).To(Equal("a"))
+
+			pth, err := m.Path("b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pth).To(Equal("/file.txt"))
+		})
+	})
+})
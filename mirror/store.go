@@ -0,0 +1,198 @@
+package mirror
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type Entry struct {
+	Id       string    `json:"id"`
+	ParentId string    `json:"parentId,omitempty"`
+	Name     string    `json:"name"`
+	IsRoot   bool      `json:"isRoot,omitempty"`
+	IsFolder bool      `json:"isFolder"`
+	Size     int64     `json:"size"`
+	ETag     string    `json:"eTag,omitempty"`
+	CTag     string    `json:"cTag,omitempty"`
+	Modified time.Time `json:"modified"`
+}
+
+// Store persists mirrored entries and the delta cursor. Changes only need to
+// be durable after Commit.
+type Store interface {
+	Get(id string) (entry *Entry, ok bool, err error)
+	Root() (entry *Entry, ok bool, err error)
+	Child(parentId string, name string) (entry *Entry, ok bool, err error)
+	Children(parentId string) (entries []*Entry, err error)
+	Put(entry *Entry) error
+	Delete(id string) error
+	Cursor() (cursor string, err error)
+	SetCursor(cursor string) error
+	Reset() error
+	Commit() error
+}
+
+func childKey(name string) string {
+	return strings.ToLower(name)
+}
+
+type MemoryStore struct {
+	mutex    sync.RWMutex
+	entries  map[string]*Entry
+	children map[string]map[string]string
+	rootId   string
+	cursor   string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:  map[string]*Entry{},
+		children: map[string]map[string]string{},
+	}
+}
+
+func (s *MemoryStore) Get(id string) (entry *Entry, ok bool, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, ok = s.entries[id]
+
+	return entry, ok, nil
+}
+
+func (s *MemoryStore) Root() (entry *Entry, ok bool, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, ok = s.entries[s.rootId]
+
+	return entry, ok, nil
+}
+
+func (s *MemoryStore) Child(parentId string, name string) (entry *Entry, ok bool, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	id, ok := s.children[parentId][childKey(name)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry, ok = s.entries[id]
+
+	return entry, ok, nil
+}
+
+func (s *MemoryStore) Children(parentId string) (entries []*Entry, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries = make([]*Entry, 0, len(s.children[parentId]))
+
+	for _, id := range s.children[parentId] {
+		entries = append(entries, s.entries[id])
+	}
+
+	return entries, nil
+}
+
+func (s *MemoryStore) Put(entry *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unlink(entry.Id)
+
+	s.entries[entry.Id] = entry
+
+	if entry.IsRoot {
+		s.rootId = entry.Id
+	} else {
+		children, ok := s.children[entry.ParentId]
+		if !ok {
+			children = map[string]string{}
+			s.children[entry.ParentId] = children
+		}
+		children[childKey(entry.Name)] = entry.Id
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unlink(id)
+
+	delete(s.entries, id)
+	delete(s.children, id)
+
+	return nil
+}
+
+func (s *MemoryStore) unlink(id string) {
+	old, ok := s.entries[id]
+	if !ok {
+		return
+	}
+
+	children := s.children[old.ParentId]
+
+	if children[childKey(old.Name)] == id {
+		delete(children, childKey(old.Name))
+
+		if len(children) == 0 {
+			delete(s.children, old.ParentId)
+		}
+	}
+
+	if s.rootId == id {
+		s.rootId = ""
+	}
+}
+
+func (s *MemoryStore) Cursor() (cursor string, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.cursor, nil
+}
+
+func (s *MemoryStore) SetCursor(cursor string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cursor = cursor
+
+	return nil
+}
+
+func (s *MemoryStore) Reset() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = map[string]*Entry{}
+	s.children = map[string]map[string]string{}
+	s.rootId = ""
+	s.cursor = ""
+
+	return nil
+}
+
+func (s *MemoryStore) Commit() error {
+	return nil
+}
+
+func (s *MemoryStore) all() []*Entry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries := make([]*Entry, 0, len(s.entries))
+
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
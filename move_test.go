@@ -0,0 +1,101 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemsMove", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var requests []string
+	var updateBody *ItemUpdateBody
+	var conflictBehavior string
+	var deleteIfMatch string
+
+	BeforeEach(func() {
+		requests = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == "PATCH":
+				conflictBehavior = r.URL.Query().Get("@microsoft.graph.conflictBehavior")
+				updateBody = &ItemUpdateBody{}
+				json.NewDecoder(r.Body).Decode(updateBody)
+				json.NewEncoder(w).Encode(&Item{Id: "src-id", Name: updateBody.Name, ParentReference: updateBody.ParentReference})
+			case r.Method == "GET" && r.URL.Path == "/drives/drive/items/src-id":
+				json.NewEncoder(w).Encode(&Item{Id: "src-id", Name: "file.txt", ETag: "etag1", ParentReference: &ItemReference{DriveId: "drive"}})
+			case r.Method == "POST":
+				conflictBehavior = r.URL.Query().Get("@microsoft.graph.conflictBehavior")
+				w.Header().Set("Location", server.URL+"/monitor")
+				w.WriteHeader(http.StatusAccepted)
+			case r.URL.Path == "/monitor":
+				json.NewEncoder(w).Encode(&AsyncOperationStatus{Status: AsyncOperationStatusCompleted, ResourceId: "copy-id"})
+			case r.Method == "GET" && r.URL.Path == "/drives/other/items/copy-id":
+				json.NewEncoder(w).Encode(&Item{Id: "copy-id", Name: "moved.txt", ParentReference: &ItemReference{DriveId: "other"}})
+			case r.Method == "DELETE":
+				deleteIfMatch = r.Header.Get("If-Match")
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.CopyPollInterval = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should move within a drive", func() {
+		item, err := client.ItemsMove(context.Background(), AddressId("src-id"), &ItemReference{Id: "dest-id"}, "moved.txt", NameConflictBehaviorRename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("moved.txt"))
+
+		Expect(requests).To(Equal([]string{"PATCH /drives/drive/items/src-id"}))
+		Expect(updateBody.ParentReference.Id).To(Equal("dest-id"))
+		Expect(conflictBehavior).To(Equal(NameConflictBehaviorRename))
+	})
+
+	It("should rename in place without a destination parent", func() {
+		item, err := client.ItemsMove(context.Background(), AddressId("src-id"), nil, "renamed.txt", NameConflictBehaviorFail)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("renamed.txt"))
+
+		Expect(requests).To(Equal([]string{"PATCH /drives/drive/items/src-id"}))
+		Expect(updateBody.ParentReference).To(BeNil())
+
+		_, err = client.ItemsMove(context.Background(), AddressId("src-id"), nil, "", NameConflictBehaviorFail)
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+	})
+
+	It("should copy and delete across drives", func() {
+		item, err := client.ItemsMove(context.Background(), AddressId("src-id"), &ItemReference{DriveId: "other", Id: "dest-id"}, "moved.txt", NameConflictBehaviorReplace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal("copy-id"))
+		Expect(item.ParentReference.DriveId).To(Equal("other"))
+
+		Expect(requests).To(Equal([]string{
+			"GET /drives/drive/items/src-id",
+			"POST /drives/drive/items/src-id/copy",
+			"GET /monitor",
+			"GET /drives/other/items/copy-id",
+			"DELETE /drives/drive/items/src-id",
+		}))
+		Expect(conflictBehavior).To(Equal(NameConflictBehaviorReplace))
+		Expect(deleteIfMatch).To(Equal("etag1"))
+	})
+})
@@ -0,0 +1,213 @@
+package onedriveclient
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxPathLength = 400
+)
+
+const invalidNameChars = `"*:<>?/\|`
+
+var reservedNames = map[string]bool{
+	".lock":       true,
+	"desktop.ini": true,
+	"con":         true,
+	"prn":         true,
+	"aux":         true,
+	"nul":         true,
+	"com0":        true,
+	"com1":        true,
+	"com2":        true,
+	"com3":        true,
+	"com4":        true,
+	"com5":        true,
+	"com6":        true,
+	"com7":        true,
+	"com8":        true,
+	"com9":        true,
+	"lpt0":        true,
+	"lpt1":        true,
+	"lpt2":        true,
+	"lpt3":        true,
+	"lpt4":        true,
+	"lpt5":        true,
+	"lpt6":        true,
+	"lpt7":        true,
+	"lpt8":        true,
+	"lpt9":        true,
+}
+
+type InvalidNameError struct {
+	Name   string
+	Reason string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("invalid name %q: %s", e.Name, e.Reason)
+}
+
+func IsInvalidNameError(err error) (invalidNameErr *InvalidNameError, ok bool) {
+	if ine, ok := err.(*InvalidNameError); ok {
+		return ine, true
+	} else {
+		return nil, false
+	}
+}
+
+func ValidateName(name string) error {
+	invalid := func(reason string) error {
+		return &InvalidNameError{
+			Name:   name,
+			Reason: reason,
+		}
+	}
+
+	if name == "" {
+		return invalid("name is empty")
+	}
+
+	if name == "." || name == ".." {
+		return invalid("name is a relative path segment")
+	}
+
+	if i := strings.IndexAny(name, invalidNameChars); i != -1 {
+		return invalid(fmt.Sprintf("name contains invalid character %q", name[i]))
+	}
+
+	if strings.HasPrefix(name, " ") || strings.HasSuffix(name, " ") {
+		return invalid("name starts or ends with a space")
+	}
+
+	lower := strings.ToLower(name)
+
+	if reservedNames[lower] {
+		return invalid("name is reserved")
+	}
+
+	if strings.Contains(lower, "_vti_") {
+		return invalid("name contains _vti_")
+	}
+
+	if strings.HasPrefix(name, "~$") {
+		return invalid("name starts with ~$")
+	}
+
+	return nil
+}
+
+func ValidatePath(pth string) error {
+	pth = NormalizePath(pth)
+
+	if utf8.RuneCountInString(pth) > MaxPathLength {
+		return &InvalidNameError{
+			Name:   pth,
+			Reason: fmt.Sprintf("path is longer than %d characters", MaxPathLength),
+		}
+	}
+
+	if pth == "/" {
+		return nil
+	}
+
+	for _, name := range strings.Split(pth[1:], "/") {
+		if err := ValidateName(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateChildName validates name and, if the path of the folder at address
+// is known, the length of the resulting path.
+func ValidateChildName(address Address, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	pth := "/"
+
+	if address != AddressRoot {
+		var ok bool
+		if _, pth, ok = address.splitPath(); !ok {
+			return nil
+		}
+	}
+
+	return ValidatePath(path.Join(NormalizePath(pth), name))
+}
+
+// sanitizeQuote marks a rune that was already a look-alike in the original
+// name so that SanitizeName and UnsanitizeName stay reversible.
+const sanitizeQuote = '‛'
+
+const sanitizeSpace = '␠'
+
+var sanitizeReplacements = map[rune]rune{
+	'"':  '＂',
+	'*':  '＊',
+	':':  '：',
+	'<':  '＜',
+	'>':  '＞',
+	'?':  '？',
+	'/':  '／',
+	'\\': '＼',
+	'|':  '｜',
+}
+
+var sanitizeOriginals = func() map[rune]rune {
+	m := map[rune]rune{
+		sanitizeSpace: ' ',
+	}
+	for orig, repl := range sanitizeReplacements {
+		m[repl] = orig
+	}
+	return m
+}()
+
+func SanitizeName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if repl, ok := sanitizeReplacements[r]; ok {
+			b.WriteRune(repl)
+		} else if r == ' ' && (i == 0 || i == len(runes)-1) {
+			b.WriteRune(sanitizeSpace)
+		} else if _, ok := sanitizeOriginals[r]; ok || r == sanitizeQuote {
+			b.WriteRune(sanitizeQuote)
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func UnsanitizeName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == sanitizeQuote && i+1 < len(runes) {
+			i++
+			b.WriteRune(runes[i])
+		} else if orig, ok := sanitizeOriginals[r]; ok {
+			b.WriteRune(orig)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
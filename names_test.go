@@ -0,0 +1,112 @@
+package onedriveclient
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Names", func() {
+	Describe("ValidateName", func() {
+		It("should accept valid names", func() {
+			for _, name := range []string{"file.txt", "new folder", "a.lock", "con.txt", "~file", "ünicode ☃"} {
+				Expect(ValidateName(name)).To(Succeed(), name)
+			}
+		})
+
+		It("should reject invalid names", func() {
+			for _, name := range []string{"", ".", "..", "a:b", "a*b", `a"b`, "a<b", "a>b", "a?b", "a/b", `a\b`, "a|b", " a", "a ", ".lock", "desktop.ini", "Desktop.ini", "CON", "nul", "com1", "LPT9", "a_vti_b", "~$doc.docx"} {
+				err := ValidateName(name)
+				Expect(err).To(HaveOccurred(), name)
+
+				ine, ok := IsInvalidNameError(err)
+				Expect(ok).To(BeTrue())
+				Expect(ine.Name).To(Equal(name))
+			}
+		})
+	})
+
+	Describe("ValidatePath", func() {
+		It("should validate every segment", func() {
+			Expect(ValidatePath("/")).To(Succeed())
+			Expect(ValidatePath("/dir/file.txt")).To(Succeed())
+			Expect(ValidatePath("/dir/CON/file.txt")).NotTo(Succeed())
+		})
+
+		It("should reject long paths", func() {
+			Expect(ValidatePath("/" + strings.Repeat("a", MaxPathLength-1))).To(Succeed())
+			Expect(ValidatePath("/" + strings.Repeat("a", MaxPathLength))).NotTo(Succeed())
+		})
+	})
+
+	Describe("ValidateChildName", func() {
+		It("should check the path length including the name", func() {
+			dir := "/" + strings.Repeat("d", 200)
+			name := strings.Repeat("n", MaxPathLength-len(dir)-1)
+
+			Expect(ValidateChildName(AddressPath(dir), name)).To(Succeed())
+			Expect(ValidateChildName(AddressPath(dir), name+"n")).NotTo(Succeed())
+
+			Expect(ValidateChildName(AddressRoot, strings.Repeat("n", MaxPathLength-1))).To(Succeed())
+			Expect(ValidateChildName(AddressRoot, strings.Repeat("n", MaxPathLength))).NotTo(Succeed())
+		})
+
+		It("should only check the name if the folder path is unknown", func() {
+			Expect(ValidateChildName(AddressId("123"), strings.Repeat("n", MaxPathLength))).To(Succeed())
+			Expect(ValidateChildName(AddressId("123"), "a:b")).NotTo(Succeed())
+		})
+	})
+
+	Describe("SanitizeName", func() {
+		It("should produce valid names", func() {
+			sanitized := SanitizeName(` a"*:<>?/\|b `)
+			Expect(sanitized).To(Equal("␠a＂＊：＜＞？／＼｜b␠"))
+			Expect(ValidateName(sanitized)).To(Succeed())
+		})
+
+		It("should be reversible", func() {
+			for _, name := range []string{"file.txt", ` a"*:<>?/\|b `, "a：b", "‛", "a‛：b␠", " ", "a b"} {
+				Expect(UnsanitizeName(SanitizeName(name))).To(Equal(name), name)
+			}
+		})
+	})
+
+	Describe("OneDrive", func() {
+		It("should validate names before any request", func() {
+			client := NewOneDrive(&OneDriveAuth{})
+
+			_, err := client.ItemsCreate(context.Background(), AddressRoot, &ItemCreateBody{Name: "a:b"})
+			_, ok := IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+
+			_, err = client.ItemsUpdate(context.Background(), AddressRoot, &ItemUpdateBody{Name: "CON"})
+			_, ok = IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+
+			_, err = client.ItemsUpload(context.Background(), AddressRoot, "desktop.ini", NameConflictBehaviorReplace, strings.NewReader(""), 0)
+			_, ok = IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should validate the path length including the name before any request", func() {
+			client := NewOneDrive(&OneDriveAuth{})
+
+			dir := "/" + strings.Repeat("d", 200)
+			name := strings.Repeat("n", MaxPathLength-len(dir))
+
+			_, err := client.ItemsUpload(context.Background(), AddressPath(dir), name, NameConflictBehaviorReplace, strings.NewReader(""), 0)
+			_, ok := IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+
+			_, err = client.ItemsCreate(context.Background(), AddressPath(dir), &ItemCreateBody{Name: name})
+			_, ok = IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+
+			_, err = client.ItemsUpdate(context.Background(), AddressPath(dir+"/file.txt"), &ItemUpdateBody{Name: name})
+			_, ok = IsInvalidNameError(err)
+			Expect(ok).To(BeTrue())
+		})
+	})
+})
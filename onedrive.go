@@ -8,6 +8,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/koofr/go-httpclient"
@@ -16,16 +18,46 @@ import (
 )
 
 const (
-	DefaultMaxFragmentSize = 60 * 1024 * 1024
+	DefaultMaxFragmentSize     = FragmentSizeLimit
+	DefaultCopyPollInterval    = 500 * time.Millisecond
+	DefaultCopyMaxPollInterval = 10 * time.Second
+	DefaultCopyTimeout         = 1 * time.Hour
+	DefaultWatchMinInterval    = 5 * time.Second
+	DefaultWatchMaxInterval    = 1 * time.Minute
+	DefaultDownloadChunkSize   = 8 * 1024 * 1024
+	DefaultDownloadParallelism = 4
+	DefaultDownloadRetries     = 3
+	DefaultDownloadRetryDelay  = 1 * time.Second
+	DefaultUploadRetries       = 5
+	DefaultUploadRetryDelay    = 1 * time.Second
 )
 
 type OneDrive struct {
 	ApiClient                *httpclient.HTTPClient
 	Auth                     *OneDriveAuth
 	MaxFragmentSize          int64
+	AdaptiveFragmentSize     bool
+	AdaptiveFragmentDuration time.Duration
 	DriveId                  string
 	IsGraph                  bool
 	UnusedFilenameMaxRetries int
+	CopyPollInterval         time.Duration
+	CopyMaxPollInterval      time.Duration
+	CopyTimeout              time.Duration
+	WatchMinInterval         time.Duration
+	WatchMaxInterval         time.Duration
+	DownloadChunkSize        int64
+	DownloadParallelism      int
+	DownloadRetries          int
+	DownloadRetryDelay       time.Duration
+	UploadRetries            int
+	UploadRetryDelay         time.Duration
+
+	// VerifyHashes enables content hash verification in ItemsUpload*,
+	// ItemsUploadResume*, ItemsDownload, ItemsDownloadItem (if the writer is
+	// also an io.ReaderAt), ItemsDownloadFile, whole-content ItemsContent and
+	// sequential reads of ItemReader.
+	VerifyHashes bool
 }
 
 func NewOneDrive(auth *OneDriveAuth) (c *OneDrive) {
@@ -40,6 +72,17 @@ func NewOneDrive(auth *OneDriveAuth) (c *OneDrive) {
 		DriveId:                  "",
 		IsGraph:                  false,
 		UnusedFilenameMaxRetries: 100,
+		CopyPollInterval:         DefaultCopyPollInterval,
+		CopyMaxPollInterval:      DefaultCopyMaxPollInterval,
+		CopyTimeout:              DefaultCopyTimeout,
+		WatchMinInterval:         DefaultWatchMinInterval,
+		WatchMaxInterval:         DefaultWatchMaxInterval,
+		DownloadChunkSize:        DefaultDownloadChunkSize,
+		DownloadParallelism:      DefaultDownloadParallelism,
+		DownloadRetries:          DefaultDownloadRetries,
+		DownloadRetryDelay:       DefaultDownloadRetryDelay,
+		UploadRetries:            DefaultUploadRetries,
+		UploadRetryDelay:         DefaultUploadRetryDelay,
 	}
 
 	return c
@@ -57,11 +100,30 @@ func NewOneDriveGraph(auth *OneDriveAuth, driveId string) (c *OneDrive) {
 		DriveId:                  driveId,
 		IsGraph:                  true,
 		UnusedFilenameMaxRetries: 100,
+		CopyPollInterval:         DefaultCopyPollInterval,
+		CopyMaxPollInterval:      DefaultCopyMaxPollInterval,
+		CopyTimeout:              DefaultCopyTimeout,
+		WatchMinInterval:         DefaultWatchMinInterval,
+		WatchMaxInterval:         DefaultWatchMaxInterval,
+		DownloadChunkSize:        DefaultDownloadChunkSize,
+		DownloadParallelism:      DefaultDownloadParallelism,
+		DownloadRetries:          DefaultDownloadRetries,
+		DownloadRetryDelay:       DefaultDownloadRetryDelay,
+		UploadRetries:            DefaultUploadRetries,
+		UploadRetryDelay:         DefaultUploadRetryDelay,
 	}
 
 	return c
 }
 
+func (c *OneDrive) nameConflictBehaviorParam() string {
+	if c.IsGraph {
+		return "@microsoft.graph.conflictBehavior"
+	}
+
+	return "@name.conflictBehavior"
+}
+
 func (c *OneDrive) HandleError(err error) error {
 	return HandleError(err)
 }
@@ -128,16 +190,37 @@ func (c *OneDrive) Drive(ctx context.Context) (drive *Drive, err error) {
 }
 
 func (c *OneDrive) ItemsGet(ctx context.Context, address Address) (item *Item, err error) {
+	return c.ItemsGetConditional(ctx, address, nil)
+}
+
+func (c *OneDrive) ItemsGetConditional(ctx context.Context, address Address, cond *Precondition) (item *Item, err error) {
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "GET",
 		Path:           address.String(c.DriveId),
-		ExpectedStatus: []int{http.StatusOK},
-		RespEncoding:   httpclient.EncodingJSON,
-		RespValue:      &item,
+		Headers:        cond.headers(nil),
+		ExpectedStatus: []int{http.StatusOK, http.StatusNotModified},
 	}
 
-	_, err = c.Request(req)
+	res, err := c.Request(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
+	buf, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(buf, &item)
 
 	if err != nil {
 		return nil, err
@@ -164,10 +247,27 @@ func (c *OneDrive) ItemsGetHead(ctx context.Context, address Address) (exists bo
 }
 
 func (c *OneDrive) ItemsUpdate(ctx context.Context, address Address, itemUpdate *ItemUpdateBody) (item *Item, err error) {
+	return c.ItemsUpdateConditional(ctx, address, itemUpdate, nil)
+}
+
+func (c *OneDrive) ItemsUpdateConditional(ctx context.Context, address Address, itemUpdate *ItemUpdateBody, cond *Precondition) (item *Item, err error) {
+	if itemUpdate.Name != "" {
+		parent := address.Parent()
+		if itemUpdate.ParentReference != nil {
+			// the new parent path is not known
+			parent = AddressId(itemUpdate.ParentReference.Id)
+		}
+
+		if err = ValidateChildName(parent, itemUpdate.Name); err != nil {
+			return nil, err
+		}
+	}
+
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "PATCH",
 		Path:           address.String(c.DriveId),
+		Headers:        cond.headers(nil),
 		ExpectedStatus: []int{http.StatusOK},
 		ReqEncoding:    httpclient.EncodingJSON,
 		ReqValue:       itemUpdate,
@@ -175,6 +275,11 @@ func (c *OneDrive) ItemsUpdate(ctx context.Context, address Address, itemUpdate
 		RespValue:      &item,
 	}
 
+	if itemUpdate.NameConflictBehavior != "" {
+		req.Params = make(url.Values)
+		req.Params.Set(c.nameConflictBehaviorParam(), itemUpdate.NameConflictBehavior)
+	}
+
 	_, err = c.Request(req)
 
 	if err != nil {
@@ -184,11 +289,79 @@ func (c *OneDrive) ItemsUpdate(ctx context.Context, address Address, itemUpdate
 	return item, nil
 }
 
+// ItemsMove moves src into dstParent, optionally renaming it to newName. A
+// nil dstParent renames the item in place. Moves within a drive are done with
+// a single PATCH, moves to another drive copy the item and then delete src. If
+// src cannot be deleted the copied item is returned together with the error.
+func (c *OneDrive) ItemsMove(ctx context.Context, src Address, dstParent *ItemReference, newName string, nameConflictBehavior string) (item *Item, err error) {
+	if dstParent == nil && newName == "" {
+		return nil, fmt.Errorf("move needs a destination parent or a new name")
+	}
+
+	if dstParent == nil || dstParent.DriveId == "" || dstParent.DriveId == c.DriveId {
+		return c.ItemsUpdate(ctx, src, &ItemUpdateBody{
+			Name:                 newName,
+			ParentReference:      dstParent,
+			NameConflictBehavior: nameConflictBehavior,
+		})
+	}
+
+	srcItem, err := c.ItemsGet(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	if srcItem.ParentReference != nil && strings.EqualFold(srcItem.ParentReference.DriveId, dstParent.DriveId) {
+		return c.ItemsUpdate(ctx, AddressId(srcItem.Id), &ItemUpdateBody{
+			Name:                 newName,
+			ParentReference:      dstParent,
+			NameConflictBehavior: nameConflictBehavior,
+		})
+	}
+
+	if newName != "" {
+		if err = ValidateName(newName); err != nil {
+			return nil, err
+		}
+	}
+
+	op, err := c.ItemsCopyOperation(ctx, AddressId(srcItem.Id), &ItemCopyBody{
+		Name:                 newName,
+		ParentReference:      dstParent,
+		NameConflictBehavior: nameConflictBehavior,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err = c.AsyncOperationAwait(ctx, op, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cond *Precondition
+	if srcItem.ETag != "" {
+		cond = IfMatch(srcItem.ETag)
+	}
+
+	err = c.ItemsDeleteConditional(ctx, AddressId(srcItem.Id), cond)
+	if err != nil {
+		return item, err
+	}
+
+	return item, nil
+}
+
 func (c *OneDrive) ItemsDelete(ctx context.Context, address Address) (err error) {
+	return c.ItemsDeleteConditional(ctx, address, nil)
+}
+
+func (c *OneDrive) ItemsDeleteConditional(ctx context.Context, address Address, cond *Precondition) (err error) {
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "DELETE",
 		Path:           address.String(c.DriveId),
+		Headers:        cond.headers(nil),
 		ExpectedStatus: []int{http.StatusNoContent},
 		RespConsume:    true,
 	}
@@ -203,6 +376,10 @@ func (c *OneDrive) ItemsDelete(ctx context.Context, address Address) (err error)
 }
 
 func (c *OneDrive) ItemsCreate(ctx context.Context, address Address, body *ItemCreateBody) (item *Item, err error) {
+	if err = ValidateChildName(address, body.Name); err != nil {
+		return nil, err
+	}
+
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "POST",
@@ -223,6 +400,75 @@ func (c *OneDrive) ItemsCreate(ctx context.Context, address Address, body *ItemC
 	return item, nil
 }
 
+func (c *OneDrive) ItemsCreatePath(ctx context.Context, pth string) (item *Item, err error) {
+	pth = NormalizePath(pth)
+
+	if err = ValidatePath(pth); err != nil {
+		return nil, err
+	}
+
+	if pth == "/" {
+		return c.ItemsGet(ctx, AddressRoot)
+	}
+
+	item, err = c.ItemsGet(ctx, AddressPath(pth))
+	if err == nil {
+		if item.Folder == nil {
+			return nil, fmt.Errorf("%s is not a folder", pth)
+		}
+
+		return item, nil
+	}
+	if !IsErrorItemNotFound(err) {
+		return nil, err
+	}
+
+	parent := AddressRoot
+	current := "/"
+	missing := false
+
+	for _, name := range strings.Split(pth[1:], "/") {
+		current = path.Join(current, name)
+
+		if !missing {
+			item, err = c.ItemsGet(ctx, AddressPath(current))
+			if IsErrorItemNotFound(err) {
+				missing = true
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		if missing {
+			body := &ItemCreateBody{
+				Name: name,
+			}
+
+			if c.IsGraph {
+				body.GraphNameConflictBehavior = NameConflictBehaviorFail
+			} else {
+				body.NameConflictBehavior = NameConflictBehaviorFail
+			}
+
+			item, err = c.ItemsCreate(ctx, parent, body)
+			if IsErrorNameAlreadyExists(err) {
+				item, err = c.ItemsGet(ctx, AddressPath(current))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if item.Folder == nil {
+			return nil, fmt.Errorf("%s is not a folder", current)
+		}
+
+		parent = AddressId(item.Id)
+	}
+
+	return item, nil
+}
+
 func (c *OneDrive) ItemsChildren(ctx context.Context, address Address, link string) (res *ItemCollectionPage, err error) {
 	req := &httpclient.RequestData{
 		Context:        ctx,
@@ -257,6 +503,15 @@ func (c *OneDrive) ItemsCopy(ctx context.Context, address Address, body *ItemCop
 		path = address.Subpath("/copy").String(c.DriveId)
 	}
 
+	if body.ParentReference != nil && body.ParentReference.Id != "" && body.ParentReference.DriveId == "" && c.DriveId != "" {
+		parentReference := *body.ParentReference
+		parentReference.DriveId = c.DriveId
+
+		bodyCopy := *body
+		bodyCopy.ParentReference = &parentReference
+		body = &bodyCopy
+	}
+
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "POST",
@@ -268,6 +523,11 @@ func (c *OneDrive) ItemsCopy(ctx context.Context, address Address, body *ItemCop
 		RespConsume:    true,
 	}
 
+	if body.NameConflictBehavior != "" {
+		req.Params = make(url.Values)
+		req.Params.Set(c.nameConflictBehaviorParam(), body.NameConflictBehavior)
+	}
+
 	res, err := c.Request(req)
 
 	if err != nil {
@@ -280,6 +540,10 @@ func (c *OneDrive) ItemsCopy(ctx context.Context, address Address, body *ItemCop
 }
 
 func (c *OneDrive) ItemsCopyStatus(ctx context.Context, monitorUrl string) (status *AsyncOperationStatus, item *Item, err error) {
+	return c.itemsCopyStatus(ctx, monitorUrl, c.DriveId)
+}
+
+func (c *OneDrive) itemsCopyStatus(ctx context.Context, monitorUrl string, driveId string) (status *AsyncOperationStatus, item *Item, err error) {
 	if c.IsGraph {
 		req := &httpclient.RequestData{
 			Context:         ctx,
@@ -297,6 +561,16 @@ func (c *OneDrive) ItemsCopyStatus(ctx context.Context, monitorUrl string) (stat
 			return nil, nil, err
 		}
 
+		if status.Status == AsyncOperationStatusCompleted && (status.ResourceLocation != "" || status.ResourceId != "") {
+			item, err = c.itemsCopyResource(ctx, status, driveId)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return nil, item, nil
+		}
+
 		return status, nil, nil
 	}
 
@@ -352,26 +626,92 @@ func (c *OneDrive) ItemsCopyStatus(ctx context.Context, monitorUrl string) (stat
 	return nil, item, nil
 }
 
+func (c *OneDrive) itemsCopyResource(ctx context.Context, status *AsyncOperationStatus, driveId string) (item *Item, err error) {
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "GET",
+		ExpectedStatus: []int{http.StatusOK},
+		RespEncoding:   httpclient.EncodingJSON,
+		RespValue:      &item,
+	}
+
+	if status.ResourceLocation != "" {
+		req.FullURL = status.ResourceLocation
+	} else {
+		req.Path = AddressId(status.ResourceId).String(driveId)
+	}
+
+	_, err = c.Request(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
 func (c *OneDrive) ItemsCopyAwait(ctx context.Context, monitorUrl string) (item *Item, err error) {
-	for i := 0; i < 100; i++ {
-		// TODO handle ctx cancelation
-		time.Sleep(500 * time.Millisecond)
+	return c.ItemsCopyAwaitProgress(ctx, monitorUrl, nil)
+}
+
+func (c *OneDrive) ItemsCopyAwaitProgress(ctx context.Context, monitorUrl string, onProgress func(status *AsyncOperationStatus)) (item *Item, err error) {
+	return c.itemsCopyAwait(ctx, monitorUrl, c.DriveId, onProgress)
+}
 
-		status, item, err := c.ItemsCopyStatus(ctx, monitorUrl)
+func (c *OneDrive) itemsCopyAwait(ctx context.Context, monitorUrl string, driveId string, onProgress func(status *AsyncOperationStatus)) (item *Item, err error) {
+	awaitCtx := ctx
+
+	if c.CopyTimeout > 0 {
+		var cancel context.CancelFunc
+		awaitCtx, cancel = context.WithTimeout(ctx, c.CopyTimeout)
+		defer cancel()
+	}
+
+	awaitErr := func(err error) error {
+		if ctx.Err() == nil && awaitCtx.Err() != nil {
+			return ErrCopyTimeout
+		}
+		return err
+	}
+
+	interval := c.CopyPollInterval
+	if interval <= 0 {
+		interval = DefaultCopyPollInterval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-awaitCtx.Done():
+			return nil, awaitErr(awaitCtx.Err())
+		case <-timer.C:
+		}
+
+		status, item, err := c.itemsCopyStatus(awaitCtx, monitorUrl, driveId)
 		if err != nil {
-			return nil, err
+			return nil, awaitErr(err)
 		}
 		if item != nil {
 			return item, nil
 		}
+		if onProgress != nil {
+			onProgress(status)
+		}
 		if status.Status == AsyncOperationStatusFailed {
-			return nil, fmt.Errorf("copy failed")
+			return nil, &AsyncOperationFailedError{Status: status}
 		} else if c.IsGraph && status.Status == AsyncOperationStatusCompleted {
 			return nil, ErrCompletedNoItem
 		}
-	}
 
-	return nil, fmt.Errorf("copy progress too long")
+		interval *= 2
+		if c.CopyMaxPollInterval > 0 && interval > c.CopyMaxPollInterval {
+			interval = c.CopyMaxPollInterval
+		}
+
+		timer.Reset(interval)
+	}
 }
 
 func (c *OneDrive) ItemsDelta(ctx context.Context, address Address, link string, token string) (res *DeltaCollectionPage, err error) {
@@ -408,44 +748,105 @@ func (c *OneDrive) ItemsDelta(ctx context.Context, address Address, link string,
 }
 
 func (c *OneDrive) ItemsContent(ctx context.Context, address Address, span *ioutils.FileSpan) (reader io.ReadCloser, size int64, err error) {
+	return c.ItemsContentConditional(ctx, address, span, nil)
+}
+
+// ItemsContentConditional gets item content in span, which can be nil for the
+// whole content. If VerifyHashes is set, the whole content is verified when
+// the reader reaches EOF, which needs an additional request for the item.
+func (c *OneDrive) ItemsContentConditional(ctx context.Context, address Address, span *ioutils.FileSpan, cond *Precondition) (reader io.ReadCloser, size int64, err error) {
+	var item *Item
+	if c.VerifyHashes && span == nil {
+		item, err = c.ItemsGet(ctx, address)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	reader, info, err := c.ItemsContentRange(ctx, address, RangeFromFileSpan(span), cond)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if item != nil {
+		if info.ETag != "" && item.ETag != "" && normalizeETag(info.ETag) != normalizeETag(item.ETag) {
+			reader.Close()
+			return nil, 0, ErrItemModified
+		}
+
+		reader = newHashVerifyingReader(reader, item)
+	}
+
+	return reader, info.Size, nil
+}
+
+// ItemsContentRange gets item content in rng, which can be nil for the whole
+// content. A range that does not overlap the content fails with
+// *RangeNotSatisfiableError.
+func (c *OneDrive) ItemsContentRange(ctx context.Context, address Address, rng *ContentRange, cond *Precondition) (reader io.ReadCloser, info *ContentInfo, err error) {
 	req := &httpclient.RequestData{
 		Context:        ctx,
 		Method:         "GET",
 		Path:           address.Subpath("/content").String(c.DriveId),
-		ExpectedStatus: []int{http.StatusFound, http.StatusOK, http.StatusPartialContent},
+		Headers:        cond.headers(make(http.Header)),
+		ExpectedStatus: []int{http.StatusFound, http.StatusOK, http.StatusPartialContent, http.StatusNotModified, http.StatusRequestedRangeNotSatisfiable},
 	}
 
-	if span != nil {
-		req.Headers = make(http.Header)
-		req.Headers.Set("Range", fmt.Sprintf("bytes=%d-%d", span.Start, span.End))
+	if rng != nil {
+		if err := rng.validate(); err != nil {
+			return nil, nil, err
+		}
+
+		req.Headers.Set("Range", rng.header())
 	}
 
 	res, err := c.Request(req)
 
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		res.Body.Close()
+		return nil, nil, ErrNotModified
+	case http.StatusRequestedRangeNotSatisfiable:
+		res.Body.Close()
+
+		rangeErr := &RangeNotSatisfiableError{
+			Range: rng,
+			Total: -1,
+		}
+		if _, _, total, ok := parseContentRange(res.Header.Get("Content-Range")); ok {
+			rangeErr.Total = total
+		}
+
+		return nil, nil, rangeErr
 	}
 
-	return res.Body, res.ContentLength, nil
+	return res.Body, contentInfoFromResponse(res), nil
 }
 
 func (c *OneDrive) ItemsUploadCreateSession(ctx context.Context, address Address, body BaseCreateSessionBody) (uploadSession *UploadSession, err error) {
+	return c.itemsUploadCreateSession(ctx, address, body, nil)
+}
+
+func (c *OneDrive) itemsUploadCreateSession(ctx context.Context, address Address, body BaseCreateSessionBody, cond *Precondition) (uploadSession *UploadSession, err error) {
 	uploadSession = &UploadSession{}
 
 	var path string
 
 	if address.Type == AddressTypeId {
-		if c.IsGraph {
-			path = address.Subpath(":/" + body.GetName() + ":/createUploadSession").String(c.DriveId)
-		} else {
-			path = address.Subpath(":/" + body.GetName() + ":/upload.createSession").String(c.DriveId)
+		address, err = address.Join(body.GetName())
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	if c.IsGraph {
+		path = address.Subpath("/createUploadSession").String(c.DriveId)
 	} else {
-		if c.IsGraph {
-			path = address.Subpath("/createUploadSession").String(c.DriveId)
-		} else {
-			path = address.Subpath("/upload.createSession").String(c.DriveId)
-		}
+		path = address.Subpath("/upload.createSession").String(c.DriveId)
 	}
 
 	if c.IsGraph {
@@ -456,6 +857,7 @@ func (c *OneDrive) ItemsUploadCreateSession(ctx context.Context, address Address
 		Context:        ctx,
 		Method:         "POST",
 		Path:           path,
+		Headers:        cond.headers(nil),
 		ExpectedStatus: []int{http.StatusOK, http.StatusPartialContent},
 		ReqEncoding:    httpclient.EncodingJSON,
 		ReqValue:       body,
@@ -529,14 +931,56 @@ func (c *OneDrive) ItemsUploadSessionFinish(ctx context.Context, uploadSession *
 }
 
 func (c *OneDrive) ItemsUpload(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
+	return c.ItemsUploadConditional(ctx, address, name, nameConflictBehavior, content, size, nil)
+}
+
+func (c *OneDrive) ItemsUploadConditional(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64, cond *Precondition) (item *Item, err error) {
+	if err = ValidateChildName(address, name); err != nil {
+		return nil, err
+	}
+
+	// seekable content may be read more than once if fragments are retried, so
+	// it is hashed separately after the upload
+	var hasher *ItemHasher
+	var verifyContent io.ReaderAt
+	if c.VerifyHashes {
+		if contentAt, ok := uploadReaderAt(content); ok {
+			verifyContent = contentAt
+		} else {
+			hasher = NewItemHasher()
+			content = io.TeeReader(content, hasher)
+		}
+	}
 
 	if size == 0 {
-		return c.ItemsUploadSimple(ctx, address, name, nameConflictBehavior, content, size)
+		item, err = c.itemsUploadSimple(ctx, address, name, nameConflictBehavior, content, size, cond)
+	} else {
+		item, err = c.itemsUploadSession(ctx, address, name, nameConflictBehavior, content, size, cond)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if hasher != nil {
+		if err = hasher.Verify(item); err != nil {
+			return nil, err
+		}
+	}
+
+	if verifyContent != nil {
+		if err = VerifyItemHash(item, io.NewSectionReader(verifyContent, 0, size)); err != nil {
+			return nil, err
+		}
 	}
-	return c.ItemsUploadSession(ctx, address, name, nameConflictBehavior, content, size)
+
+	return item, nil
 }
 
 func (c *OneDrive) ItemsUploadSimple(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
+	return c.itemsUploadSimple(ctx, address, name, nameConflictBehavior, content, size, nil)
+}
+
+func (c *OneDrive) itemsUploadSimple(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64, cond *Precondition) (item *Item, err error) {
 	item = &Item{}
 
 	childrenMap := map[string]*Item{}
@@ -588,11 +1032,12 @@ func (c *OneDrive) ItemsUploadSimple(ctx context.Context, address Address, name
 	var path string
 
 	if address.Type == AddressTypeId {
-		if c.IsGraph {
-			path = address.Subpath(":/" + name + ":/content").String(c.DriveId)
-		} else {
-			path = address.Subpath(":/" + name + ":/content").String(c.DriveId)
+		address, err := address.Join(name)
+		if err != nil {
+			return nil, err
 		}
+
+		path = address.Subpath("/content").String(c.DriveId)
 	} else {
 		return nil, fmt.Errorf("not implemented because I have no idea how to properly do it")
 	}
@@ -601,6 +1046,7 @@ func (c *OneDrive) ItemsUploadSimple(ctx context.Context, address Address, name
 		Context:        ctx,
 		Method:         "PUT",
 		Path:           path,
+		Headers:        cond.headers(nil),
 		ExpectedStatus: []int{http.StatusOK, http.StatusCreated},
 		ReqReader:      content,
 		RespEncoding:   httpclient.EncodingJSON,
@@ -617,15 +1063,12 @@ func (c *OneDrive) ItemsUploadSimple(ctx context.Context, address Address, name
 }
 
 func (c *OneDrive) ItemsUploadSession(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64) (item *Item, err error) {
-	var createSessionBody BaseCreateSessionBody = &CreateSessionBody{
-		Item: ChunkedUploadSessionDescriptor{
-			NameConflictBehavior: nameConflictBehavior,
-			Name:                 name,
-		},
-	}
+	return c.itemsUploadSession(ctx, address, name, nameConflictBehavior, content, size, nil)
+}
 
+func (c *OneDrive) createSessionBody(name string, nameConflictBehavior string) BaseCreateSessionBody {
 	if c.IsGraph {
-		createSessionBody = &GraphCreateSessionBody{
+		return &GraphCreateSessionBody{
 			Item: GraphChunkedUploadSessionDescriptor{
 				NameConflictBehavior: nameConflictBehavior,
 				Name:                 name,
@@ -633,18 +1076,48 @@ func (c *OneDrive) ItemsUploadSession(ctx context.Context, address Address, name
 		}
 	}
 
-	uploadSession, err := c.ItemsUploadCreateSession(ctx, address, createSessionBody)
+	return &CreateSessionBody{
+		Item: ChunkedUploadSessionDescriptor{
+			NameConflictBehavior: nameConflictBehavior,
+			Name:                 name,
+		},
+	}
+}
+
+func (c *OneDrive) itemsUploadSession(ctx context.Context, address Address, name string, nameConflictBehavior string, content io.Reader, size int64, cond *Precondition) (item *Item, err error) {
+	uploadSession, err := c.itemsUploadCreateSession(ctx, address, c.createSessionBody(name, nameConflictBehavior), cond)
 	if err != nil {
 		return nil, err
 	}
 
+	if contentAt, ok := uploadReaderAt(content); ok {
+		lookup := func(ctx context.Context) (*Item, error) {
+			itemAddress, err := address.Join(name)
+			if err != nil {
+				return nil, err
+			}
+
+			return c.ItemsGet(ctx, itemAddress)
+		}
+
+		return c.itemsUploadSessionAt(ctx, uploadSession, contentAt, 0, size, lookup)
+	}
+
+	return c.itemsUploadSessionFrom(ctx, uploadSession, content, 0, size)
+}
+
+// itemsUploadSessionFrom uploads content, which must start at offset start,
+// in fragments of at most MaxFragmentSize aligned to FragmentSizeUnit.
+func (c *OneDrive) itemsUploadSessionFrom(ctx context.Context, uploadSession *UploadSession, content io.Reader, start int64, size int64) (item *Item, err error) {
 	reader := ioutils.NewEofReader(content)
 
-	uploaded := int64(0)
+	sizer := c.newFragmentSizer()
+
+	uploaded := start
 
 	for !reader.Eof {
 		start := uploaded
-		partSize := c.MaxFragmentSize
+		partSize := sizer.next()
 		last := false
 
 		if left := size - uploaded; left <= partSize {
@@ -667,10 +1140,14 @@ func (c *OneDrive) ItemsUploadSession(ctx context.Context, address Address, name
 			return item, nil
 		}
 
+		fragmentStart := time.Now()
+
 		err = c.ItemsUploadSessionAppend(ctx, uploadSession, partReader, start, end, size)
 		if err != nil {
 			return nil, err
 		}
+
+		sizer.uploaded(partSize, time.Since(fragmentStart))
 	}
 
 	return nil, fmt.Errorf("invalid state")
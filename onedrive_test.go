@@ -197,13 +197,8 @@ var _ = Describe("OneDrive", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			item, err := client.ItemsCopyAwait(context.Background(), monitorUrl)
-
-			if isGraph {
-				Expect(err).To(Equal(ErrCompletedNoItem))
-			} else {
-				Expect(err).NotTo(HaveOccurred())
-				Expect(item.Name).To(Equal("file copy.txt"))
-			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Name).To(Equal("file copy.txt"))
 
 			_, err = client.ItemsGet(context.Background(), AddressPath("/file copy.txt"))
 			Expect(err).NotTo(HaveOccurred())
@@ -222,14 +217,8 @@ var _ = Describe("OneDrive", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			item, err := client.ItemsCopyAwait(context.Background(), monitorUrl)
-
-			if isGraph {
-				Expect(err).To(Equal(ErrCompletedNoItem))
-			} else {
-				Expect(err).NotTo(HaveOccurred())
-
-				Expect(item.Name).To(Equal("file copy.txt"))
-			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Name).To(Equal("file copy.txt"))
 
 			_, err = client.ItemsGet(context.Background(), AddressPath("/dest/file copy.txt"))
 			Expect(err).NotTo(HaveOccurred())
@@ -246,13 +235,8 @@ var _ = Describe("OneDrive", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			item, err := client.ItemsCopyAwait(context.Background(), monitorUrl)
-			if isGraph {
-				Expect(err).To(Equal(ErrCompletedNoItem))
-			} else {
-				Expect(err).NotTo(HaveOccurred())
-
-				Expect(item.Name).To(Equal("dir copy"))
-			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Name).To(Equal("dir copy"))
 
 			_, err = client.ItemsGet(context.Background(), AddressPath("/dir copy"))
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,42 @@
+package onedriveclient
+
+import (
+	"net/http"
+)
+
+type Precondition struct {
+	IfMatch     string
+	IfNoneMatch string
+}
+
+func IfMatch(eTag string) *Precondition {
+	return &Precondition{
+		IfMatch: eTag,
+	}
+}
+
+func IfNoneMatch(eTag string) *Precondition {
+	return &Precondition{
+		IfNoneMatch: eTag,
+	}
+}
+
+func (p *Precondition) headers(headers http.Header) http.Header {
+	if p == nil {
+		return headers
+	}
+
+	if headers == nil {
+		headers = make(http.Header)
+	}
+
+	if p.IfMatch != "" {
+		headers.Set("If-Match", p.IfMatch)
+	}
+
+	if p.IfNoneMatch != "" {
+		headers.Set("If-None-Match", p.IfNoneMatch)
+	}
+
+	return headers
+}
@@ -0,0 +1,72 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Precondition", func() {
+	var server *httptest.Server
+	var client *OneDrive
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if match := r.Header.Get("If-Match"); match != "" && match != "etag1" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(`{"error":{"code":"resourceModified","message":"ETag does not match current item's value"}}`))
+				return
+			}
+
+			if r.Header.Get("If-None-Match") == "etag1" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			switch r.Method {
+			case "DELETE":
+				w.WriteHeader(http.StatusNoContent)
+			case "GET":
+				w.Write([]byte("12345"))
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "file-id", Name: "file.txt", ETag: "etag2"})
+			}
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should update if eTag matches", func() {
+		item, err := client.ItemsUpdateConditional(context.Background(), AddressId("file-id"), &ItemUpdateBody{Name: "renamed.txt"}, IfMatch("etag1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.ETag).To(Equal("etag2"))
+	})
+
+	It("should not delete if eTag does not match", func() {
+		err := client.ItemsDeleteConditional(context.Background(), AddressId("file-id"), IfMatch("etag0"))
+		Expect(err).To(HaveOccurred())
+		Expect(IsErrorPreconditionFailed(err)).To(BeTrue())
+
+		err = client.ItemsDeleteConditional(context.Background(), AddressId("file-id"), IfMatch("etag1"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not download if not modified", func() {
+		_, _, err := client.ItemsContentConditional(context.Background(), AddressId("file-id"), nil, IfNoneMatch("etag1"))
+		Expect(err).To(Equal(ErrNotModified))
+
+		reader, _, err := client.ItemsContentConditional(context.Background(), AddressId("file-id"), nil, IfNoneMatch("etag0"))
+		Expect(err).NotTo(HaveOccurred())
+		reader.Close()
+	})
+})
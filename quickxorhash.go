@@ -0,0 +1,112 @@
+package onedriveclient
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	QuickXorHashSize      = 20
+	QuickXorHashBlockSize = 64
+
+	quickXorShift          = 11
+	quickXorWidthInBits    = 8 * QuickXorHashSize
+	quickXorCells          = (quickXorWidthInBits-1)/64 + 1
+	quickXorBitsInLastCell = quickXorWidthInBits % 64
+)
+
+// quickXorHash implements the QuickXorHash used by OneDrive for Business.
+// Every input byte is xored into a 160 bit circular buffer, shifted by 11
+// bits from the previous byte, and the total length is xored into the last
+// 8 bytes of the result.
+type quickXorHash struct {
+	data        [quickXorCells]uint64
+	shiftSoFar  int
+	lengthSoFar uint64
+}
+
+func NewQuickXorHash() hash.Hash {
+	return &quickXorHash{}
+}
+
+func (h *quickXorHash) Write(p []byte) (n int, err error) {
+	vectorArrayIndex := h.shiftSoFar / 64
+	vectorOffset := h.shiftSoFar % 64
+
+	iterations := len(p)
+	if iterations > quickXorWidthInBits {
+		iterations = quickXorWidthInBits
+	}
+
+	for i := 0; i < iterations; i++ {
+		isLastCell := vectorArrayIndex == quickXorCells-1
+
+		bitsInVectorCell := 64
+		if isLastCell {
+			bitsInVectorCell = quickXorBitsInLastCell
+		}
+
+		var xored byte
+		for j := i; j < len(p); j += quickXorWidthInBits {
+			xored ^= p[j]
+		}
+
+		if vectorOffset <= bitsInVectorCell-8 {
+			h.data[vectorArrayIndex] ^= uint64(xored) << vectorOffset
+		} else {
+			nextIndex := vectorArrayIndex + 1
+			if isLastCell {
+				nextIndex = 0
+			}
+
+			low := bitsInVectorCell - vectorOffset
+
+			h.data[vectorArrayIndex] ^= uint64(xored) << vectorOffset
+			h.data[nextIndex] ^= uint64(xored) >> low
+		}
+
+		vectorOffset += quickXorShift
+		for vectorOffset >= bitsInVectorCell {
+			if isLastCell {
+				vectorArrayIndex = 0
+			} else {
+				vectorArrayIndex++
+			}
+			vectorOffset -= bitsInVectorCell
+		}
+	}
+
+	h.shiftSoFar = (h.shiftSoFar + quickXorShift*(len(p)%quickXorWidthInBits)) % quickXorWidthInBits
+	h.lengthSoFar += uint64(len(p))
+
+	return len(p), nil
+}
+
+func (h *quickXorHash) Sum(b []byte) []byte {
+	var sum [QuickXorHashSize + 4]byte
+
+	for i, cell := range h.data {
+		binary.LittleEndian.PutUint64(sum[i*8:], cell)
+	}
+
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], h.lengthSoFar)
+
+	for i, l := range length {
+		sum[QuickXorHashSize-len(length)+i] ^= l
+	}
+
+	return append(b, sum[:QuickXorHashSize]...)
+}
+
+func (h *quickXorHash) Reset() {
+	*h = quickXorHash{}
+}
+
+func (h *quickXorHash) Size() int {
+	return QuickXorHashSize
+}
+
+func (h *quickXorHash) BlockSize() int {
+	return QuickXorHashBlockSize
+}
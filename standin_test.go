@@ -0,0 +1,269 @@
+package onedriveclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+func newStandInOneDrive(serverURL string, driveId string) *OneDrive {
+	client := NewOneDriveGraph(&OneDriveAuth{
+		AccessToken: "token",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, driveId)
+
+	client.ApiClient.BaseURL, _ = url.Parse(serverURL)
+
+	return client
+}
+
+type standInTree struct {
+	items    map[string]*Item
+	paths    map[string]string
+	nextId   int
+	requests []string
+
+	beforeCreate func(pth string)
+}
+
+func newStandInTree() *standInTree {
+	t := &standInTree{
+		items: map[string]*Item{},
+		paths: map[string]string{},
+	}
+
+	t.add("/", &Folder{})
+
+	return t
+}
+
+func (t *standInTree) add(pth string, folder *Folder) *Item {
+	t.nextId++
+
+	item := &Item{
+		Id:     fmt.Sprintf("id%d", t.nextId),
+		Name:   path.Base(pth),
+		Folder: folder,
+	}
+
+	if pth == "/" {
+		item.Id = "root"
+	}
+
+	t.items[item.Id] = item
+	t.paths[pth] = item.Id
+
+	return item
+}
+
+func (t *standInTree) pathOf(id string) string {
+	for pth, itemId := range t.paths {
+		if itemId == id {
+			return pth
+		}
+	}
+
+	return ""
+}
+
+func (t *standInTree) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: code, Message: code}})
+}
+
+func (t *standInTree) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.requests = append(t.requests, r.Method+" "+r.URL.Path)
+
+	p := strings.TrimPrefix(r.URL.Path, "/drives/drive")
+
+	var item *Item
+
+	if strings.HasPrefix(p, "/root:") {
+		pth := strings.TrimSuffix(strings.TrimPrefix(p, "/root:"), ":")
+		if id, ok := t.paths[pth]; ok {
+			item = t.items[id]
+		}
+	} else if strings.HasPrefix(p, "/items/") {
+		id := strings.TrimSuffix(strings.TrimPrefix(p, "/items/"), "/children")
+		item = t.items[id]
+	}
+
+	if item == nil {
+		t.writeError(w, http.StatusNotFound, ErrorCodeItemNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "POST" && strings.HasSuffix(p, "/children") {
+		body := &ItemCreateBody{}
+		json.NewDecoder(r.Body).Decode(body)
+
+		pth := path.Join(t.pathOf(item.Id), body.Name)
+		if t.beforeCreate != nil {
+			t.beforeCreate(pth)
+		}
+		if _, ok := t.paths[pth]; ok {
+			t.writeError(w, http.StatusConflict, ErrorCodeNameAlreadyExists)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(t.add(pth, &Folder{}))
+		return
+	}
+
+	json.NewEncoder(w).Encode(item)
+}
+
+// standInUploadServer implements the upload session endpoints for a single
+// session. fail can reject a fragment before it is read by returning a status
+// code other than 0.
+type standInUploadServer struct {
+	mutex    sync.Mutex
+	name     string
+	data     []byte
+	size     int64
+	requests []string
+
+	// committed is set once the final fragment is uploaded, after which the
+	// session is gone and the item can be fetched by name
+	committed bool
+
+	fail func(start int64, end int64) int
+}
+
+func (s *standInUploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	nextExpected := func() *UploadSession {
+		return &UploadSession{
+			NextExpectedRanges: []string{fmt.Sprintf("%d-", len(s.data))},
+		}
+	}
+
+	switch {
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/createUploadSession"):
+		s.requests = append(s.requests, "POST create")
+
+		body := &GraphCreateSessionBody{}
+		json.NewDecoder(r.Body).Decode(body)
+		s.name = path.Base(strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/createUploadSession"), ":"))
+		s.data = nil
+		s.committed = false
+
+		json.NewEncoder(w).Encode(&UploadSession{
+			UploadUrl:          "http://" + r.Host + "/upload/session",
+			ExpirationDateTime: time.Now().Add(time.Hour),
+		})
+
+	case r.Method == "GET" && s.committed && strings.HasSuffix(r.URL.Path, "/"+s.name+":"):
+		s.requests = append(s.requests, "GET item")
+
+		json.NewEncoder(w).Encode(s.item())
+
+	case r.URL.Path != "/upload/session":
+		w.WriteHeader(http.StatusNotFound)
+
+	case s.committed:
+		s.requests = append(s.requests, r.Method+" gone")
+
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: ErrorCodeItemNotFound, Message: "itemNotFound"}})
+
+	case r.Method == "GET":
+		s.requests = append(s.requests, "GET status")
+
+		json.NewEncoder(w).Encode(nextExpected())
+
+	case r.Method == "DELETE":
+		s.requests = append(s.requests, "DELETE")
+
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "PUT":
+		var start, end, size int64
+		fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &size)
+
+		s.requests = append(s.requests, fmt.Sprintf("PUT %d-%d", start, end))
+		s.size = size
+
+		data, _ := io.ReadAll(r.Body)
+
+		if s.fail != nil {
+			if status := s.fail(start, end); status != 0 {
+				w.WriteHeader(status)
+				json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: "failed", Message: "failed"}})
+				return
+			}
+		}
+
+		if start != int64(len(s.data)) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: "invalidRange", Message: "invalidRange"}})
+			return
+		}
+
+		if int64(len(data)) != end-start+1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.data = append(s.data, data...)
+
+		if int64(len(s.data)) < size {
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(nextExpected())
+			return
+		}
+
+		s.committed = true
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(s.item())
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *standInUploadServer) item() *Item {
+	h := NewItemHasher()
+	h.Write(s.data)
+
+	return &Item{
+		Id:   "uploaded",
+		Name: s.name,
+		Size: s.size,
+		File: &File{Hashes: h.Hashes()},
+	}
+}
+
+// standInUploadContent returns size bytes of content and the ranges it is
+// uploaded in with FragmentSizeUnit fragments.
+func standInUploadContent(size int64) (content string, puts []string) {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = "0123456789abcdefghijklmnopqrstuvwxyz"[i%36]
+	}
+
+	for start := int64(0); start < size; start += FragmentSizeUnit {
+		end := start + FragmentSizeUnit - 1
+		if end >= size {
+			end = size - 1
+		}
+		puts = append(puts, fmt.Sprintf("PUT %d-%d", start, end))
+	}
+
+	return string(data), puts
+}
@@ -0,0 +1,241 @@
+package onedriveclient
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/koofr/go-httpclient"
+)
+
+const (
+	SubscriptionChangeTypeUpdated = "updated"
+)
+
+var ErrSubscriptionsNotSupported = errors.New("Subscriptions are only supported by Microsoft Graph")
+
+type Subscription struct {
+	Id                 string    `json:"id,omitempty"`
+	Resource           string    `json:"resource,omitempty"`
+	ChangeType         string    `json:"changeType,omitempty"`
+	NotificationUrl    string    `json:"notificationUrl,omitempty"`
+	ExpirationDateTime time.Time `json:"expirationDateTime"`
+	ClientState        string    `json:"clientState,omitempty"`
+}
+
+type SubscriptionCollectionPage struct {
+	Value    []*Subscription `json:"value"`
+	NextLink string          `json:"@odata.nextLink"`
+}
+
+type Notification struct {
+	SubscriptionId                 string    `json:"subscriptionId"`
+	SubscriptionExpirationDateTime time.Time `json:"subscriptionExpirationDateTime"`
+	ClientState                    string    `json:"clientState"`
+	ChangeType                     string    `json:"changeType"`
+	Resource                       string    `json:"resource"`
+	TenantId                       string    `json:"tenantId,omitempty"`
+}
+
+type NotificationCollection struct {
+	Value []*Notification `json:"value"`
+}
+
+// subscriptionsUrl returns the /subscriptions endpoint which is not scoped
+// to /me like the rest of the Graph API.
+func (c *OneDrive) subscriptionsUrl(id string) string {
+	u := *c.ApiClient.BaseURL
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/me") + "/subscriptions"
+	u.RawPath = ""
+
+	if id != "" {
+		u.Path += "/" + id
+	}
+
+	return u.String()
+}
+
+// SubscriptionResource returns the resource for change notifications on the
+// root of the client's drive.
+func (c *OneDrive) SubscriptionResource() string {
+	if c.DriveId == "" {
+		return "/me/drive/root"
+	}
+
+	return "/drives/" + c.DriveId + "/root"
+}
+
+func (c *OneDrive) SubscriptionsCreate(ctx context.Context, subscription *Subscription) (created *Subscription, err error) {
+	if !c.IsGraph {
+		return nil, ErrSubscriptionsNotSupported
+	}
+
+	body := *subscription
+
+	if body.Resource == "" {
+		body.Resource = c.SubscriptionResource()
+	}
+
+	if body.ChangeType == "" {
+		body.ChangeType = SubscriptionChangeTypeUpdated
+	}
+
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "POST",
+		FullURL:        c.subscriptionsUrl(""),
+		ExpectedStatus: []int{http.StatusCreated},
+		ReqEncoding:    httpclient.EncodingJSON,
+		ReqValue:       &body,
+		RespEncoding:   httpclient.EncodingJSON,
+		RespValue:      &created,
+	}
+
+	_, err = c.Request(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (c *OneDrive) SubscriptionsRenew(ctx context.Context, id string, expirationDateTime time.Time) (subscription *Subscription, err error) {
+	if !c.IsGraph {
+		return nil, ErrSubscriptionsNotSupported
+	}
+
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "PATCH",
+		FullURL:        c.subscriptionsUrl(id),
+		ExpectedStatus: []int{http.StatusOK},
+		ReqEncoding:    httpclient.EncodingJSON,
+		ReqValue:       &Subscription{ExpirationDateTime: expirationDateTime},
+		RespEncoding:   httpclient.EncodingJSON,
+		RespValue:      &subscription,
+	}
+
+	_, err = c.Request(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (c *OneDrive) SubscriptionsList(ctx context.Context) (subscriptions []*Subscription, err error) {
+	if !c.IsGraph {
+		return nil, ErrSubscriptionsNotSupported
+	}
+
+	link := c.subscriptionsUrl("")
+
+	for link != "" {
+		var page *SubscriptionCollectionPage
+
+		req := &httpclient.RequestData{
+			Context:        ctx,
+			Method:         "GET",
+			FullURL:        link,
+			ExpectedStatus: []int{http.StatusOK},
+			RespEncoding:   httpclient.EncodingJSON,
+			RespValue:      &page,
+		}
+
+		_, err = c.Request(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, page.Value...)
+		link = page.NextLink
+	}
+
+	return subscriptions, nil
+}
+
+func (c *OneDrive) SubscriptionsDelete(ctx context.Context, id string) (err error) {
+	if !c.IsGraph {
+		return ErrSubscriptionsNotSupported
+	}
+
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "DELETE",
+		FullURL:        c.subscriptionsUrl(id),
+		ExpectedStatus: []int{http.StatusNoContent},
+		RespConsume:    true,
+	}
+
+	_, err = c.Request(req)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotificationHandler receives change notifications. It answers the
+// validation handshake, drops notifications with a wrong clientState and
+// calls OnNotifications with the rest, which is where a delta fetch should be
+// triggered. OnNotifications should return quickly because the service
+// expects a response within a few seconds.
+type NotificationHandler struct {
+	ClientState     string
+	OnNotifications func(notifications []*Notification)
+}
+
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if token := r.URL.Query().Get("validationToken"); token != "" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(token))
+		return
+	}
+
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	collection := &NotificationCollection{}
+
+	if err := json.NewDecoder(r.Body).Decode(collection); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	notifications := make([]*Notification, 0, len(collection.Value))
+
+	for _, notification := range collection.Value {
+		if subtle.ConstantTimeCompare([]byte(notification.ClientState), []byte(h.ClientState)) == 1 {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	if len(notifications) > 0 && h.OnNotifications != nil {
+		h.OnNotifications(notifications)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// DeltaTrigger returns an OnNotifications callback that signals trigger
+// without blocking. Give trigger a buffer of one so that notifications
+// arriving while a delta fetch is pending are coalesced into one fetch.
+func DeltaTrigger(trigger chan<- struct{}) func(notifications []*Notification) {
+	return func(notifications []*Notification) {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscriptions", func() {
+	Describe("OneDrive", func() {
+		var server *httptest.Server
+		var client *OneDrive
+		var requests []string
+		var created *Subscription
+
+		BeforeEach(func() {
+			requests = nil
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.Method {
+				case "POST":
+					created = &Subscription{}
+					json.NewDecoder(r.Body).Decode(created)
+					created.Id = "sub-id"
+					w.WriteHeader(http.StatusCreated)
+					json.NewEncoder(w).Encode(created)
+				case "PATCH":
+					subscription := &Subscription{}
+					json.NewDecoder(r.Body).Decode(subscription)
+					subscription.Id = "sub-id"
+					json.NewEncoder(w).Encode(subscription)
+				case "GET":
+					json.NewEncoder(w).Encode(&SubscriptionCollectionPage{Value: []*Subscription{{Id: "sub-id"}}})
+				case "DELETE":
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+
+			client = newStandInOneDrive(server.URL+"/v1.0/me", "drive")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should manage subscriptions", func() {
+			expiration := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+			subscription, err := client.SubscriptionsCreate(context.Background(), &Subscription{
+				NotificationUrl:    "https://example.com/notify",
+				ExpirationDateTime: expiration,
+				ClientState:        "secret",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subscription.Id).To(Equal("sub-id"))
+			Expect(created.Resource).To(Equal("/drives/drive/root"))
+			Expect(created.ChangeType).To(Equal(SubscriptionChangeTypeUpdated))
+
+			renewed, err := client.SubscriptionsRenew(context.Background(), "sub-id", expiration.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewed.ExpirationDateTime).To(BeTemporally("==", expiration.Add(time.Hour)))
+
+			subscriptions, err := client.SubscriptionsList(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subscriptions).To(HaveLen(1))
+
+			Expect(client.SubscriptionsDelete(context.Background(), "sub-id")).To(Succeed())
+
+			Expect(requests).To(Equal([]string{
+				"POST /v1.0/subscriptions",
+				"PATCH /v1.0/subscriptions/sub-id",
+				"GET /v1.0/subscriptions",
+				"DELETE /v1.0/subscriptions/sub-id",
+			}))
+		})
+
+		It("should not be supported by legacy client", func() {
+			legacy := NewOneDrive(&OneDriveAuth{})
+
+			_, err := legacy.SubscriptionsCreate(context.Background(), &Subscription{})
+			Expect(err).To(Equal(ErrSubscriptionsNotSupported))
+		})
+	})
+
+	Describe("NotificationHandler", func() {
+		var handler *NotificationHandler
+		var received []*Notification
+		var trigger chan struct{}
+
+		BeforeEach(func() {
+			received = nil
+			trigger = make(chan struct{}, 1)
+
+			onDelta := DeltaTrigger(trigger)
+
+			handler = &NotificationHandler{
+				ClientState: "secret",
+				OnNotifications: func(notifications []*Notification) {
+					received = append(received, notifications...)
+					onDelta(notifications)
+				},
+			}
+		})
+
+		It("should answer validation handshake", func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("POST", "/notify?validationToken="+url.QueryEscape("token 1"), nil))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Content-Type")).To(Equal("text/plain"))
+			Expect(rec.Body.String()).To(Equal("token 1"))
+		})
+
+		It("should verify client state and trigger delta", func() {
+			body, _ := json.Marshal(&NotificationCollection{Value: []*Notification{
+				{SubscriptionId: "sub-id", ClientState: "secret", Resource: "/drives/drive/root"},
+				{SubscriptionId: "other", ClientState: "wrong"},
+			}})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("POST", "/notify", bytes.NewReader(body)))
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("POST", "/notify", bytes.NewReader(body)))
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+			Expect(received).To(HaveLen(2))
+			Expect(received[0].SubscriptionId).To(Equal("sub-id"))
+			Expect(trigger).To(HaveLen(1))
+		})
+
+		It("should reject invalid payloads", func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("POST", "/notify", bytes.NewBufferString("{")))
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
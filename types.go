@@ -75,8 +75,10 @@ type Folder struct {
 }
 
 type Hashes struct {
-	Crc32Hash string `json:"crc32Hash"`
-	Sha1Hash  string `json:"sha1Hash"`
+	Crc32Hash    string `json:"crc32Hash"`
+	Sha1Hash     string `json:"sha1Hash"`
+	Sha256Hash   string `json:"sha256Hash"`
+	QuickXorHash string `json:"quickXorHash"`
 }
 
 type File struct {
@@ -90,6 +92,9 @@ type ItemReference struct {
 	Path    string `json:"path,omitempty"`
 }
 
+type Root struct {
+}
+
 type Deleted struct {
 	State string `json:"state"`
 }
@@ -129,6 +134,9 @@ type Item struct {
 	File                 *File           `json:"file,omitempty"`
 	FileSystemInfo       *FileSystemInfo `json:"fileSystemInfo,omitempty"`
 	Folder               *Folder         `json:"folder,omitempty"`
+	Root                 *Root           `json:"root,omitempty"`
+	DownloadUrl          string          `json:"@content.downloadUrl,omitempty"`
+	GraphDownloadUrl     string          `json:"@microsoft.graph.downloadUrl,omitempty"`
 	// Audio
 	// Image
 	// Location
@@ -143,19 +151,23 @@ type Item struct {
 }
 
 type ItemUpdateBody struct {
-	Name            string          `json:"name,omitempty"`
-	ParentReference *ItemReference  `json:"parentReference,omitempty"`
-	FileSystemInfo  *FileSystemInfo `json:"fileSystemInfo,omitempty"`
+	Name                 string          `json:"name,omitempty"`
+	ParentReference      *ItemReference  `json:"parentReference,omitempty"`
+	FileSystemInfo       *FileSystemInfo `json:"fileSystemInfo,omitempty"`
+	NameConflictBehavior string          `json:"-"`
 }
 
 type ItemCreateBody struct {
-	Name   string `json:"name,omitempty"`
-	Folder Folder `json:"folder,omitempty"`
+	Name                      string `json:"name,omitempty"`
+	Folder                    Folder `json:"folder,omitempty"`
+	NameConflictBehavior      string `json:"@name.conflictBehavior,omitempty"`
+	GraphNameConflictBehavior string `json:"@microsoft.graph.conflictBehavior,omitempty"`
 }
 
 type ItemCopyBody struct {
-	Name            string         `json:"name,omitempty"`
-	ParentReference *ItemReference `json:"parentReference,omitempty"`
+	Name                 string         `json:"name,omitempty"`
+	ParentReference      *ItemReference `json:"parentReference,omitempty"`
+	NameConflictBehavior string         `json:"-"`
 }
 
 type ItemCollectionPage struct {
@@ -195,4 +207,8 @@ type AsyncOperationStatus struct {
 	Operation          string  `json:"operation"`
 	PercentageComplete float64 `json:"percentageComplete"`
 	Status             string  `json:"status"`
+	StatusDescription  string  `json:"statusDescription,omitempty"`
+	ErrorCode          string  `json:"errorCode,omitempty"`
+	ResourceId         string  `json:"resourceId,omitempty"`
+	ResourceLocation   string  `json:"resourceLocation,omitempty"`
 }
@@ -0,0 +1,172 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koofr/go-httpclient"
+)
+
+// ResumableUpload is a handle to an upload session that can be stored and
+// resumed by another process, as long as the session has not expired.
+type ResumableUpload struct {
+	UploadUrl            string    `json:"uploadUrl"`
+	ExpirationDateTime   time.Time `json:"expirationDateTime"`
+	Name                 string    `json:"name"`
+	Size                 int64     `json:"size"`
+	NameConflictBehavior string    `json:"nameConflictBehavior,omitempty"`
+}
+
+func ParseResumableUpload(data []byte) (upload *ResumableUpload, err error) {
+	upload = &ResumableUpload{}
+
+	if err = json.Unmarshal(data, upload); err != nil {
+		return nil, err
+	}
+
+	if upload.UploadUrl == "" {
+		return nil, fmt.Errorf("resumable upload url missing")
+	}
+
+	return upload, nil
+}
+
+func (u *ResumableUpload) Marshal() ([]byte, error) {
+	return json.Marshal(u)
+}
+
+func (u *ResumableUpload) Expired(now time.Time) bool {
+	return !u.ExpirationDateTime.IsZero() && !now.Before(u.ExpirationDateTime)
+}
+
+func (u *ResumableUpload) session() *UploadSession {
+	return &UploadSession{
+		UploadUrl:          u.UploadUrl,
+		ExpirationDateTime: u.ExpirationDateTime,
+	}
+}
+
+// NextExpectedOffset returns the start of the first range the server still
+// expects.
+func (s *UploadSession) NextExpectedOffset() (offset int64, ok bool) {
+	if len(s.NextExpectedRanges) == 0 {
+		return 0, false
+	}
+
+	startStr, _, _ := strings.Cut(s.NextExpectedRanges[0], "-")
+
+	offset, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return offset, true
+}
+
+// ItemsUploadCreateResumable creates an upload session for a file of size
+// bytes named name in address. Content is uploaded with ItemsUploadResume.
+func (c *OneDrive) ItemsUploadCreateResumable(ctx context.Context, address Address, name string, nameConflictBehavior string, size int64) (upload *ResumableUpload, err error) {
+	if err = ValidateChildName(address, name); err != nil {
+		return nil, err
+	}
+
+	uploadSession, err := c.itemsUploadCreateSession(ctx, address, c.createSessionBody(name, nameConflictBehavior), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResumableUpload{
+		UploadUrl:            uploadSession.UploadUrl,
+		ExpirationDateTime:   uploadSession.ExpirationDateTime,
+		Name:                 name,
+		Size:                 size,
+		NameConflictBehavior: nameConflictBehavior,
+	}, nil
+}
+
+func (c *OneDrive) ItemsUploadSessionStatus(ctx context.Context, uploadUrl string) (uploadSession *UploadSession, err error) {
+	uploadSession = &UploadSession{}
+
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "GET",
+		FullURL:        uploadUrl,
+		ExpectedStatus: []int{http.StatusOK},
+		RespEncoding:   httpclient.EncodingJSON,
+		RespValue:      &uploadSession,
+	}
+
+	_, err = c.RequestUnauthorized(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if uploadSession.UploadUrl == "" {
+		uploadSession.UploadUrl = uploadUrl
+	}
+
+	return uploadSession, nil
+}
+
+func (c *OneDrive) ItemsUploadSessionCancel(ctx context.Context, uploadUrl string) (err error) {
+	req := &httpclient.RequestData{
+		Context:        ctx,
+		Method:         "DELETE",
+		FullURL:        uploadUrl,
+		ExpectedStatus: []int{http.StatusNoContent},
+		RespConsume:    true,
+	}
+
+	_, err = c.RequestUnauthorized(req)
+
+	return err
+}
+
+func (c *OneDrive) itemsUploadResumeOffset(ctx context.Context, upload *ResumableUpload) (offset int64, err error) {
+	status, err := c.ItemsUploadSessionStatus(ctx, upload.UploadUrl)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, ok := status.NextExpectedOffset()
+	if !ok {
+		return 0, fmt.Errorf("upload session has no expected ranges")
+	}
+
+	return offset, nil
+}
+
+// ItemsUploadResume uploads the rest of the file, continuing from the next
+// offset the server expects. content is the complete file.
+func (c *OneDrive) ItemsUploadResume(ctx context.Context, upload *ResumableUpload, content io.ReaderAt) (item *Item, err error) {
+	offset, err := c.itemsUploadResumeOffset(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err = c.itemsUploadSessionAt(ctx, upload.session(), content, offset, upload.Size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.VerifyHashes {
+		if err := VerifyItemHash(item, io.NewSectionReader(content, 0, upload.Size)); err != nil {
+			return nil, err
+		}
+	}
+
+	return item, nil
+}
+
+// ItemsUploadResumeSeeker is like ItemsUploadResume for sources that can only
+// be read sequentially after seeking.
+func (c *OneDrive) ItemsUploadResumeSeeker(ctx context.Context, upload *ResumableUpload, content io.ReadSeeker) (item *Item, err error) {
+	return c.ItemsUploadResume(ctx, upload, newReadSeekerAt(content, 0))
+}
@@ -0,0 +1,121 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResumableUpload", func() {
+	var server *httptest.Server
+	var upload *standInUploadServer
+	var client *OneDrive
+
+	const u = FragmentSizeUnit
+
+	content, puts := standInUploadContent(3*u + 6)
+
+	BeforeEach(func() {
+		upload = &standInUploadServer{}
+		server = httptest.NewServer(upload)
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.MaxFragmentSize = u
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should parse next expected offset", func() {
+		offset, ok := (&UploadSession{NextExpectedRanges: []string{"26-", "40-50"}}).NextExpectedOffset()
+		Expect(ok).To(BeTrue())
+		Expect(offset).To(Equal(int64(26)))
+
+		_, ok = (&UploadSession{}).NextExpectedOffset()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should resume an interrupted upload from a stored session", func() {
+		created, err := client.ItemsUploadCreateResumable(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := created.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		client.UploadRetries = 0
+
+		upload.fail = func(start int64, end int64) int {
+			if start == 2*u {
+				return http.StatusInternalServerError
+			}
+			return 0
+		}
+
+		_, err = client.ItemsUploadResume(context.Background(), created, strings.NewReader(content))
+		Expect(err).To(HaveOccurred())
+
+		upload.fail = nil
+
+		stored, err := ParseResumableUpload(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(Equal(created))
+
+		status, err := client.ItemsUploadSessionStatus(context.Background(), stored.UploadUrl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.NextExpectedRanges).To(Equal([]string{fmt.Sprintf("%d-", 2*u)}))
+
+		item, err := client.ItemsUploadResume(context.Background(), stored, strings.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("file.txt"))
+		Expect(string(upload.data)).To(Equal(content))
+
+		Expect(upload.requests).To(Equal([]string{
+			"POST create",
+			"GET status",
+			puts[0],
+			puts[1],
+			puts[2],
+			"GET status",
+			"GET status",
+			puts[2],
+			puts[3],
+		}))
+	})
+
+	It("should resume from a seekable source", func() {
+		created, err := client.ItemsUploadCreateResumable(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+
+		upload.data = []byte(content[:u])
+
+		_, err = client.ItemsUploadResumeSeeker(context.Background(), created, strings.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(upload.data)).To(Equal(content))
+	})
+
+	It("should verify hashes of the resumed upload", func() {
+		created, err := client.ItemsUploadCreateResumable(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+
+		client.VerifyHashes = true
+
+		// the server already has a different first fragment
+		upload.data = bytes.Repeat([]byte("x"), int(u))
+
+		_, err = client.ItemsUploadResumeSeeker(context.Background(), created, strings.NewReader(content))
+		_, ok := IsHashMismatchError(err)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should reject stored sessions without url", func() {
+		_, err := ParseResumableUpload([]byte(`{"name":"file.txt"}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,201 @@
+package onedriveclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readSeekerAt adapts an io.ReadSeeker to io.ReaderAt. Offsets are relative
+// to base.
+type readSeekerAt struct {
+	mutex sync.Mutex
+	rs    io.ReadSeeker
+	base  int64
+}
+
+func newReadSeekerAt(rs io.ReadSeeker, base int64) *readSeekerAt {
+	return &readSeekerAt{
+		rs:   rs,
+		base: base,
+	}
+}
+
+func (r *readSeekerAt) ReadAt(p []byte, off int64) (n int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, err = r.rs.Seek(r.base+off, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err = io.ReadFull(r.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+// uploadReaderAt returns content as an io.ReaderAt if it can be reread, so
+// that failed fragments can be retried. A seekable content is read from its
+// current position.
+func uploadReaderAt(content io.Reader) (contentAt io.ReaderAt, ok bool) {
+	if rs, ok := content.(io.ReadSeeker); ok {
+		base, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, false
+		}
+
+		return newReadSeekerAt(rs, base), true
+	}
+
+	if ra, ok := content.(io.ReaderAt); ok {
+		return ra, true
+	}
+
+	return nil, false
+}
+
+// fragmentReader records errors of reading the local content, so that they
+// are not mistaken for transport errors of the fragment request.
+type fragmentReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *fragmentReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+func isUploadSessionGone(err error) bool {
+	if ode, ok := IsOneDriveError(err); ok && ode.HttpClientError != nil {
+		return ode.HttpClientError.Got == http.StatusNotFound
+	}
+
+	return false
+}
+
+// uploadCommitted returns the uploaded item if the server committed the file
+// although the response to the final fragment was lost. The item found by
+// lookup must match the size and hashes of content.
+func (c *OneDrive) uploadCommitted(ctx context.Context, uploadSession *UploadSession, content io.ReaderAt, size int64, lookup func(ctx context.Context) (*Item, error)) (item *Item, ok bool) {
+	if lookup == nil {
+		return nil, false
+	}
+
+	if _, err := c.ItemsUploadSessionStatus(ctx, uploadSession.UploadUrl); !isUploadSessionGone(err) {
+		return nil, false
+	}
+
+	item, err := lookup(ctx)
+	if err != nil || item.Size != size {
+		return nil, false
+	}
+
+	if err := VerifyItemHash(item, io.NewSectionReader(content, 0, size)); err != nil {
+		return nil, false
+	}
+
+	return item, true
+}
+
+// itemsUploadSessionAt uploads content from offset start in fragments of at
+// most MaxFragmentSize aligned to FragmentSizeUnit. Failed fragments are retried up to UploadRetries times in total
+// with exponential backoff. If the server rejects a fragment range the offset
+// is resynchronized from the session status. If the response to the final
+// fragment is lost and the session is gone, the item is looked up with lookup
+// (if not nil) instead.
+func (c *OneDrive) itemsUploadSessionAt(ctx context.Context, uploadSession *UploadSession, content io.ReaderAt, start int64, size int64, lookup func(ctx context.Context) (*Item, error)) (item *Item, err error) {
+	offset := start
+	retries := 0
+	delay := c.UploadRetryDelay
+
+	sizer := c.newFragmentSizer()
+
+	for {
+		partSize := sizer.next()
+		if left := size - offset; left <= partSize {
+			partSize = left
+		}
+
+		end := offset + partSize - 1
+		last := end == size-1
+
+		partReader := &fragmentReader{r: io.NewSectionReader(content, offset, partSize)}
+
+		fragmentStart := time.Now()
+
+		if last {
+			item, err = c.ItemsUploadSessionFinish(ctx, uploadSession, partReader, offset, end, size)
+		} else {
+			err = c.ItemsUploadSessionAppend(ctx, uploadSession, partReader, offset, end, size)
+		}
+
+		if err == nil {
+			if last {
+				return item, nil
+			}
+
+			sizer.uploaded(partSize, time.Since(fragmentStart))
+
+			offset = end + 1
+			delay = c.UploadRetryDelay
+
+			continue
+		}
+
+		if partReader.err != nil {
+			return nil, partReader.err
+		}
+
+		if ctx.Err() != nil || !isRetryableError(err) {
+			return nil, err
+		}
+
+		if last {
+			if _, isOneDriveErr := IsOneDriveError(err); !isOneDriveErr {
+				if committed, ok := c.uploadCommitted(ctx, uploadSession, content, size, lookup); ok {
+					return committed, nil
+				}
+			}
+		}
+
+		if retries >= c.UploadRetries {
+			return nil, err
+		}
+
+		retries++
+
+		sizer.failed()
+
+		if IsErrorRangeNotSatisfiable(err) {
+			status, statusErr := c.ItemsUploadSessionStatus(ctx, uploadSession.UploadUrl)
+			if statusErr == nil {
+				if nextOffset, ok := status.NextExpectedOffset(); ok && nextOffset < size {
+					offset = nextOffset
+					continue
+				}
+			}
+		}
+
+		wait := delay
+		if retryAfter, ok := RetryAfter(err); ok {
+			wait = retryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+	}
+}
@@ -0,0 +1,204 @@
+package onedriveclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Upload retries", func() {
+	var server *httptest.Server
+	var upload *standInUploadServer
+	var client *OneDrive
+
+	const u = FragmentSizeUnit
+
+	content, puts := standInUploadContent(3*u + 6)
+
+	BeforeEach(func() {
+		upload = &standInUploadServer{}
+		server = httptest.NewServer(upload)
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.MaxFragmentSize = u
+		client.UploadRetryDelay = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	failTimes := func(fragmentStart int64, times int, status int) func(start int64, end int64) int {
+		return func(start int64, end int64) int {
+			if start == fragmentStart && times > 0 {
+				times--
+				return status
+			}
+			return 0
+		}
+	}
+
+	It("should retry failed fragments of seekable content", func() {
+		upload.fail = failTimes(u, 2, http.StatusServiceUnavailable)
+
+		item, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Name).To(Equal("file.txt"))
+		Expect(string(upload.data)).To(Equal(content))
+
+		Expect(upload.requests).To(Equal([]string{
+			"POST create",
+			puts[0],
+			puts[1],
+			puts[1],
+			puts[1],
+			puts[2],
+			puts[3],
+		}))
+	})
+
+	It("should resync the offset if the server already has the fragment", func() {
+		lost := true
+
+		upload.fail = func(start int64, end int64) int {
+			if start == u && lost {
+				lost = false
+				upload.data = append(upload.data, content[start:end+1]...)
+				return http.StatusBadGateway
+			}
+			return 0
+		}
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(upload.data)).To(Equal(content))
+
+		Expect(upload.requests).To(Equal([]string{
+			"POST create",
+			puts[0],
+			puts[1],
+			puts[1],
+			"GET status",
+			puts[2],
+			puts[3],
+		}))
+	})
+
+	It("should give up after UploadRetries", func() {
+		client.UploadRetries = 2
+		upload.fail = failTimes(u, 100, http.StatusInternalServerError)
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+		Expect(err).To(HaveOccurred())
+
+		Expect(upload.requests).To(HaveLen(5))
+	})
+
+	It("should not retry client errors", func() {
+		upload.fail = failTimes(u, 1, http.StatusNotFound)
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+		Expect(err).To(HaveOccurred())
+
+		Expect(upload.requests).To(HaveLen(3))
+	})
+
+	It("should not retry content that can't be reread", func() {
+		upload.fail = failTimes(u, 1, http.StatusServiceUnavailable)
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, bytes.NewBufferString(content), int64(len(content)))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read seekable content from its current position", func() {
+		reader := strings.NewReader("xx" + content)
+		reader.Seek(2, 0)
+
+		client.VerifyHashes = true
+		upload.fail = failTimes(2*u, 1, http.StatusServiceUnavailable)
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, reader, int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(upload.data)).To(Equal(content))
+	})
+
+	It("should not retry local read errors", func() {
+		errRead := errors.New("read failed")
+
+		reader := &failingReaderAt{content: content, failAt: u + 1, err: errRead}
+
+		_, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, reader, int64(len(content)))
+		Expect(err).To(MatchError(errRead))
+
+		// the aborted fragment request may still be handled by the server
+		upload.mutex.Lock()
+		defer upload.mutex.Unlock()
+
+		Expect(upload.data).To(HaveLen(int(u)))
+		Expect(len(upload.requests)).To(BeNumerically("<=", 3))
+	})
+
+	It("should return the committed item if the final response is lost", func() {
+		lostServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var start, end, size int64
+			fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &size)
+
+			if r.Method == "PUT" && size > 0 && end == size-1 {
+				upload.ServeHTTP(httptest.NewRecorder(), r)
+
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+
+			upload.ServeHTTP(w, r)
+		}))
+		defer lostServer.Close()
+
+		client = newStandInOneDrive(lostServer.URL, "drive")
+		client.MaxFragmentSize = u
+		client.UploadRetryDelay = time.Millisecond
+
+		item, err := client.ItemsUpload(context.Background(), AddressRoot, "file.txt", NameConflictBehaviorReplace, strings.NewReader(content), int64(len(content)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.Id).To(Equal("uploaded"))
+		Expect(item.Size).To(Equal(int64(len(content))))
+
+		Expect(upload.requests).To(Equal([]string{
+			"POST create",
+			puts[0],
+			puts[1],
+			puts[2],
+			puts[3],
+			"GET gone",
+			"GET item",
+		}))
+	})
+})
+
+// failingReaderAt fails reads of content past failAt.
+type failingReaderAt struct {
+	content string
+	failAt  int64
+	err     error
+}
+
+func (r *failingReaderAt) Read(p []byte) (n int, err error) {
+	return 0, r.err
+}
+
+func (r *failingReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off+int64(len(p)) > r.failAt {
+		return 0, r.err
+	}
+
+	return copy(p, r.content[off:]), nil
+}
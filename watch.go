@@ -0,0 +1,284 @@
+package onedriveclient
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	WatchEventCreate = "create"
+	WatchEventModify = "modify"
+	WatchEventMove   = "move"
+	WatchEventDelete = "delete"
+	WatchEventError  = "error"
+)
+
+// WatchEvent is a change reported by Watch. OldPath is set for moves and
+// renames. Err is set for WatchEventError events, which report failed polls
+// that will be retried.
+type WatchEvent struct {
+	Type    string
+	Item    *Item
+	Path    string
+	OldPath string
+	Err     error
+}
+
+type watcher struct {
+	client  *OneDrive
+	address Address
+	rootId  string
+	session *DeltaSession
+	index   *DeltaPathIndex
+	eTags   map[string]string
+	events  chan<- *WatchEvent
+}
+
+// Watch polls ItemsDelta for address and emits events until ctx is canceled,
+// after which the channel is closed. The current tree is enumerated first so
+// that creates can be told apart from modifications; no events are emitted
+// for it. The polling interval grows from WatchMinInterval to
+// WatchMaxInterval while nothing changes and honors Retry-After when
+// throttled.
+func (c *OneDrive) Watch(ctx context.Context, address Address) <-chan *WatchEvent {
+	events := make(chan *WatchEvent)
+
+	w := &watcher{
+		client:  c,
+		address: address,
+		session: c.NewDeltaSession(address, ""),
+		index:   NewDeltaPathIndex(),
+		eTags:   map[string]string{},
+		events:  events,
+	}
+
+	go w.run(ctx)
+
+	return events
+}
+
+func (w *watcher) run(ctx context.Context) {
+	defer close(w.events)
+
+	minInterval := w.client.WatchMinInterval
+	if minInterval <= 0 {
+		minInterval = DefaultWatchMinInterval
+	}
+
+	maxInterval := w.client.WatchMaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWatchMaxInterval
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	interval := minInterval
+	seeded := false
+	resyncing := false
+
+	for {
+		items, err := w.poll(ctx)
+
+		var delay time.Duration
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if IsErrorItemNotFound(err) {
+				// the watched item is gone, resyncing would not help
+				if !w.emit(ctx, &WatchEvent{Type: WatchEventError, Err: err}) {
+					return
+				}
+
+				interval = maxInterval
+				delay = interval
+			} else if IsErrorResync(err) {
+				w.session.Reset()
+				resyncing = seeded
+				delay = minInterval
+			} else if retryAfter, ok := RetryAfter(err); ok {
+				delay = retryAfter
+			} else {
+				if !w.emit(ctx, &WatchEvent{Type: WatchEventError, Err: err}) {
+					return
+				}
+
+				interval = minDuration(interval*2, maxInterval)
+				delay = interval
+			}
+		} else {
+			emitted := false
+
+			// a resync enumerates the whole tree, items that are missing from
+			// it were deleted while changes were unavailable
+			if resyncing {
+				items = append(items, w.unseen(items)...)
+				resyncing = false
+			}
+
+			for _, event := range w.apply(items) {
+				if seeded {
+					if !w.emit(ctx, event) {
+						return
+					}
+					emitted = true
+				}
+			}
+
+			seeded = true
+
+			if emitted {
+				interval = minInterval
+			} else {
+				interval = minDuration(interval*2, maxInterval)
+			}
+
+			delay = interval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// resolveRoot looks up the id of the watched item so that paths are
+// relative to it if it is not the drive root.
+func (w *watcher) resolveRoot(ctx context.Context) error {
+	if w.rootId != "" || w.address == AddressRoot {
+		return nil
+	}
+
+	item, err := w.client.ItemsGet(ctx, w.address)
+	if err != nil {
+		return err
+	}
+
+	w.rootId = item.Id
+	w.index = NewDeltaPathIndexFromSnapshot(&DeltaPathSnapshot{
+		RootId: item.Id,
+		Nodes:  map[string]*DeltaPathNode{},
+	})
+
+	return nil
+}
+
+// poll fetches all pages since the last cursor and keeps only the last
+// occurrence of every item.
+func (w *watcher) poll(ctx context.Context) (items []*Item, err error) {
+	if err = w.resolveRoot(ctx); err != nil {
+		return nil, err
+	}
+
+	positions := map[string]int{}
+
+	_, err = w.session.Sync(ctx, func(page []*Item) error {
+		for _, item := range page {
+			if i, ok := positions[item.Id]; ok {
+				items[i] = nil
+			}
+
+			positions[item.Id] = len(items)
+			items = append(items, item)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deduped := make([]*Item, 0, len(positions))
+
+	for _, item := range items {
+		if item != nil {
+			deduped = append(deduped, item)
+		}
+	}
+
+	return deduped, nil
+}
+
+// unseen returns deleted items for all known items missing from items.
+func (w *watcher) unseen(items []*Item) (deleted []*Item) {
+	seen := make(map[string]bool, len(items))
+
+	for _, item := range items {
+		seen[item.Id] = true
+	}
+
+	for id := range w.eTags {
+		if !seen[id] {
+			deleted = append(deleted, &Item{
+				Id:      id,
+				Deleted: &Deleted{},
+			})
+		}
+	}
+
+	return deleted
+}
+
+func (w *watcher) apply(items []*Item) (events []*WatchEvent) {
+	for _, change := range w.index.Apply(items) {
+		item := change.Item
+
+		if w.index.isRoot(item) {
+			continue
+		}
+
+		eTag, known := w.eTags[item.Id]
+
+		event := &WatchEvent{
+			Item:    item,
+			Path:    change.Path,
+			OldPath: change.OldPath,
+		}
+
+		switch {
+		case change.Deleted:
+			if !known {
+				continue
+			}
+			delete(w.eTags, item.Id)
+			event.Type = WatchEventDelete
+		case !known:
+			event.Type = WatchEventCreate
+		case change.OldPath != "" && change.OldPath != change.Path:
+			event.Type = WatchEventMove
+		case item.Folder == nil && item.ETag != eTag:
+			event.Type = WatchEventModify
+		}
+
+		if !change.Deleted {
+			w.eTags[item.Id] = item.ETag
+		}
+
+		if event.Type != "" {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (w *watcher) emit(ctx context.Context, event *WatchEvent) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func minDuration(a time.Duration, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,242 @@
+package onedriveclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watch", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var mutex sync.Mutex
+	var throttled int
+
+	item := func(id string, name string, parentId string, eTag string) *Item {
+		return &Item{
+			Id:              id,
+			Name:            name,
+			ETag:            eTag,
+			File:            &File{},
+			ParentReference: &ItemReference{Id: parentId},
+		}
+	}
+
+	BeforeEach(func() {
+		throttled = 0
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			page := &DeltaCollectionPage{}
+
+			switch r.URL.RequestURI() {
+			case "/drives/drive/items/root/delta":
+				page.Value = []*Item{
+					{Id: "root", Name: "root", Root: &Root{}, Folder: &Folder{}},
+					item("a", "a.txt", "root", "a1"),
+					item("b", "b.txt", "root", "b1"),
+				}
+				page.DeltaLink = server.URL + "/delta?token=t1"
+			case "/delta?token=t1":
+				if throttled == 0 {
+					throttled++
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				page.Value = []*Item{
+					item("b", "b.txt", "root", "b2"),
+					item("c", "c.txt", "root", "c1"),
+				}
+				page.NextLink = server.URL + "/delta?token=t1&page=2"
+			case "/delta?token=t1&page=2":
+				page.Value = []*Item{
+					item("b", "b.txt", "root", "b2"),
+					item("c", "d.txt", "root", "c2"),
+					{Id: "a", Deleted: &Deleted{}},
+				}
+				page.DeltaLink = server.URL + "/delta?token=t2"
+			case "/delta?token=t2":
+				page.DeltaLink = server.URL + "/delta?token=t2"
+			case "/drives/drive/root:/dir:":
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(&Item{Id: "dir", Name: "dir", Folder: &Folder{}, ParentReference: &ItemReference{Id: "root"}})
+				return
+			case "/drives/drive/root:/dir:/delta":
+				page.Value = []*Item{
+					{Id: "dir", Name: "dir", Folder: &Folder{}, ParentReference: &ItemReference{Id: "root"}},
+					item("x", "x.txt", "dir", "x1"),
+				}
+				page.DeltaLink = server.URL + "/delta?token=d1"
+			case "/delta?token=d1":
+				page.Value = []*Item{
+					{Id: "dir", Name: "dir", Folder: &Folder{}, ParentReference: &ItemReference{Id: "root"}},
+					item("y", "y.txt", "dir", "y1"),
+				}
+				page.DeltaLink = server.URL + "/delta?token=d2"
+			case "/delta?token=d2":
+				page.DeltaLink = server.URL + "/delta?token=d2"
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.WatchMinInterval = time.Millisecond
+		client.WatchMaxInterval = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should emit deduplicated events after the initial enumeration", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := client.Watch(ctx, AddressRoot)
+
+		received := []string{}
+		for len(received) < 3 {
+			event := <-events
+			received = append(received, event.Type+" "+event.OldPath+" "+event.Path)
+		}
+
+		Expect(received).To(ConsistOf(
+			"modify /b.txt /b.txt",
+			"create  /d.txt",
+			"delete /a.txt /a.txt",
+		))
+
+		cancel()
+
+		Eventually(events).Should(BeClosed())
+	})
+
+	It("should resolve paths relative to a folder address", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := client.Watch(ctx, AddressPath("/dir"))
+
+		event := <-events
+		Expect(event.Type).To(Equal(WatchEventCreate))
+		Expect(event.Path).To(Equal("/y.txt"))
+
+		Consistently(events, 50*time.Millisecond).ShouldNot(Receive())
+	})
+})
+
+var _ = Describe("Watch resync", func() {
+	var server *httptest.Server
+	var client *OneDrive
+	var mutex sync.Mutex
+	var enumerations int
+	var watchedGone bool
+
+	item := func(id string, name string, eTag string) *Item {
+		return &Item{
+			Id:              id,
+			Name:            name,
+			ETag:            eTag,
+			File:            &File{},
+			ParentReference: &ItemReference{Id: "root"},
+		}
+	}
+
+	BeforeEach(func() {
+		enumerations = 0
+		watchedGone = false
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+
+			if watchedGone {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: ErrorCodeItemNotFound, Message: "gone"}})
+				return
+			}
+
+			page := &DeltaCollectionPage{}
+
+			switch r.URL.RequestURI() {
+			case "/drives/drive/items/root/delta":
+				enumerations++
+
+				page.Value = []*Item{{Id: "root", Name: "root", Root: &Root{}, Folder: &Folder{}}}
+				if enumerations == 1 {
+					page.Value = append(page.Value, item("a", "a.txt", "a1"), item("b", "b.txt", "b1"))
+				} else {
+					page.Value = append(page.Value, item("b", "b.txt", "b2"), item("c", "c.txt", "c1"))
+				}
+				page.DeltaLink = fmt.Sprintf("%s/delta?token=t%d", server.URL, enumerations)
+			case "/delta?token=t1":
+				w.WriteHeader(http.StatusGone)
+				json.NewEncoder(w).Encode(&OneDriveError{Err: OneDriveErrorDetails{Code: "resyncRequired", Message: "Resync required"}})
+				return
+			default:
+				page.DeltaLink = server.URL + r.URL.RequestURI()
+			}
+
+			json.NewEncoder(w).Encode(page)
+		}))
+
+		client = newStandInOneDrive(server.URL, "drive")
+		client.WatchMinInterval = time.Millisecond
+		client.WatchMaxInterval = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should report items missing after a resync as deleted", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := client.Watch(ctx, AddressRoot)
+
+		received := []string{}
+		for len(received) < 3 {
+			event := <-events
+			received = append(received, event.Type+" "+event.Path)
+		}
+
+		Expect(received).To(ConsistOf(
+			"delete /a.txt",
+			"modify /b.txt",
+			"create /c.txt",
+		))
+	})
+
+	It("should report a deleted watched item as an error", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mutex.Lock()
+		watchedGone = true
+		mutex.Unlock()
+
+		events := client.Watch(ctx, AddressRoot)
+
+		event := <-events
+		Expect(event.Type).To(Equal(WatchEventError))
+		Expect(IsErrorItemNotFound(event.Err)).To(BeTrue())
+	})
+})